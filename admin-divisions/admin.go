@@ -0,0 +1,123 @@
+//	Resolves the admin codes of places (or any country and codes) to their full chain of first- to fourth-order administrative divisions,
+//	using the admin1/admin2 code files and the `ADM1`..`ADM4` places of a `mem-store`.
+package geonames_admin
+
+import (
+	"strings"
+
+	"github.com/go-geo/geonames/import-dumps"
+	"github.com/go-geo/geonames/mem-store"
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+//	An administrative division.
+type Division struct {
+	//	`1` for `ADM1` through `4` for `ADM4`
+	Level int
+
+	//	Full dotted code including the country code, eg. `DE.02.091`
+	Code string
+
+	//	Geo-name id
+	Id int64
+
+	//	Name and ASCII name (the latter equals `Name` if that is ASCII already)
+	Name, NameAscii string
+}
+
+//	The `ADM1` to `ADM4` divisions (at index `0` to `3`) of a place, each `nil` if unknown or not applicable.
+type Chain [4]*Division
+
+//	Returns the names of all known divisions in `me`, most-specific first (eg. `Oberbayern, Bavaria`).
+func (me Chain) Names(ascii bool) (names []string) {
+	for i := len(me) - 1; i >= 0; i-- {
+		if d := me[i]; d != nil {
+			if ascii {
+				names = append(names, d.NameAscii)
+			} else {
+				names = append(names, d.Name)
+			}
+		}
+	}
+	return
+}
+
+//	Returns the most-specific known division in `me`, or `nil`.
+func (me Chain) Last() *Division {
+	for i := len(me) - 1; i >= 0; i-- {
+		if me[i] != nil {
+			return me[i]
+		}
+	}
+	return nil
+}
+
+//	Resolves admin codes to `Chain`s. Safe for concurrent use.
+type Resolver struct {
+	divisions map[string]*Division
+}
+
+//	Builds a `Resolver` over all `store.Admins` and all `store.Places` of feature codes `ADM1` through `ADM4`.
+//	Where both exist for the same code, the `store.Admins` record is preferred.
+func NewResolver(store *geonames_mem.Store) (me *Resolver) {
+	me = &Resolver{divisions: make(map[string]*Division, len(store.Admins))}
+	for _, a := range store.Admins {
+		me.divisions[a.Code] = &Division{Level: strings.Count(a.Code, "."), Code: a.Code, Id: a.Id, Name: a.Name, NameAscii: ascii(a.NameAscii, a.Name)}
+	}
+	for _, p := range store.Places {
+		if level := adminLevel(p); level > 0 {
+			code := key(p.Country.Code, level, p.Admin.Code1, p.Admin.Code2, p.Admin.Code3, p.Admin.Code4)
+			if _, exists := me.divisions[code]; !exists && len(code) > 0 {
+				me.divisions[code] = &Division{Level: level, Code: code, Id: p.Id, Name: p.Name, NameAscii: ascii(p.NameAscii, p.Name)}
+			}
+		}
+	}
+	return
+}
+
+//	Returns the number of divisions known to `me`.
+func (me *Resolver) Len() int {
+	return len(me.divisions)
+}
+
+//	Returns the division with the specified full dotted `code` (eg. `DE.02.091`), or `nil`.
+func (me *Resolver) Division(code string) *Division {
+	return me.divisions[code]
+}
+
+//	Returns the divisions of `p`.
+func (me *Resolver) Place(p *geonames_parse.PlaceRec) Chain {
+	return me.Resolve(p.Country.Code, p.Admin.Code1, p.Admin.Code2, p.Admin.Code3, p.Admin.Code4)
+}
+
+//	Returns the divisions denoted by the ISO-3166 alpha-2 `country` code and up to four admin `codes` (`Code1` first, as in `PlaceRec.Admin`).
+func (me *Resolver) Resolve(country string, codes ...string) (chain Chain) {
+	for level := 1; level <= len(codes) && level <= len(chain); level++ {
+		if code := key(country, level, codes...); len(code) > 0 {
+			chain[level-1] = me.divisions[code]
+		}
+	}
+	return
+}
+
+func adminLevel(p *geonames_import.Place) int {
+	if c := p.Feature.Code; p.Feature.Class == "A" && len(c) == 4 && strings.HasPrefix(c, "ADM") && c[3] >= '1' && c[3] <= '4' {
+		return int(c[3] - '0')
+	}
+	return 0
+}
+
+func ascii(nameAscii, name string) string {
+	if len(nameAscii) > 0 {
+		return nameAscii
+	}
+	return name
+}
+
+//	Returns the full dotted code of `codes[:level]` in `country`, or `""` if `codes[level-1]` is empty.
+func key(country string, level int, codes ...string) string {
+	if level > len(codes) || len(codes[level-1]) == 0 {
+		return ""
+	}
+	return country + "." + strings.Join(codes[:level], ".")
+}
@@ -0,0 +1,275 @@
+//	Prefix autocompletion over place and postal place names (of a `mem-store`), returning the most populated matches first.
+package geonames_autocomplete
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-geo/geonames/mem-store"
+	"github.com/go-geo/geonames/search-names"
+)
+
+//	Version of the file format written by `Index.Save`.
+const FormatVersion = 1
+
+//	`Entry.Kind` values
+const (
+	KindPlace = iota
+	KindPostal
+)
+
+//	Controls which names `NewIndex` includes.
+type Options struct {
+	//	Whether to include all `NamesAlt` of each place, not just `Name` and `NameAscii`
+	NamesAlt bool
+
+	//	Whether to include the (distinct per country) `PlaceName`s of postal codes not already covered by a place
+	Postals bool
+
+	//	Places with a smaller population are excluded
+	MinPopulation int64
+
+	//	If not empty, only places of these feature classes are included (eg. `"PA"`)
+	FeatureClasses string
+}
+
+//	An autocompletion candidate.
+type Entry struct {
+	//	`geonames_search.Fold`ed name, the sort key
+	Key string
+
+	//	Display name (for places, always their primary `Name`)
+	Name string
+
+	//	The name variant that `Key` was derived from
+	Match string
+
+	//	`KindPlace` or `KindPostal`
+	Kind uint8
+
+	//	`geonames_import.Place.Id` or `geonames_import.Postal.Ref`
+	Id int64
+
+	//	ISO-3166 alpha-2 country code
+	Country string
+
+	//	Feature class and code, eg. `P.PPLC` (empty for postal place names)
+	Feature string
+
+	//	Population (`0` for postal place names)
+	Population int64
+
+	//	Coordinates
+	LonLat []float64
+}
+
+//	Restricts the results of `Index.Complete`. All non-empty conditions must be met.
+type Filter struct {
+	//	Acceptable ISO-3166 alpha-2 country codes
+	Countries []string
+
+	//	Acceptable feature classes, eg. `"P"`
+	FeatureClasses string
+
+	//	Acceptable feature codes, eg. `PPLC`
+	FeatureCodes []string
+}
+
+func (me *Filter) accepts(e *Entry) bool {
+	if len(me.Countries) > 0 && !in(me.Countries, e.Country) {
+		return false
+	}
+	if len(me.FeatureClasses) > 0 && (len(e.Feature) == 0 || !strings.Contains(me.FeatureClasses, e.Feature[:1])) {
+		return false
+	}
+	if len(me.FeatureCodes) > 0 && (len(e.Feature) < 3 || !in(me.FeatureCodes, e.Feature[2:])) {
+		return false
+	}
+	return true
+}
+
+func in(vals []string, s string) bool {
+	for _, v := range vals {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+//	A read-only prefix index, safe for concurrent use.
+type Index struct {
+	//	All entries, sorted by `Key` (then by descending `Population`)
+	Entries []Entry
+
+	//	Maximum `Population` per node of a segment tree over `Entries`: leaves at `[len(Entries), 2*len(Entries))`, node `i`'s children at `2i` and `2i+1`
+	maxPops []int64
+}
+
+//	Builds an `Index` over the places (and optionally postal place names) in `store`. `opts` may be `nil`.
+func NewIndex(store *geonames_mem.Store, opts *Options) (me *Index) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	type countryKey struct{ country, key string }
+	var (
+		keys []string
+		seen map[countryKey]bool // place keys per country, only needed to skip postal place names duplicating them
+	)
+	if opts.Postals {
+		seen = map[countryKey]bool{}
+	}
+	me = &Index{}
+	for _, p := range store.Places {
+		if p.Population < opts.MinPopulation || (len(opts.FeatureClasses) > 0 && !strings.Contains(opts.FeatureClasses, p.Feature.Class)) || len(p.Feature.Class) == 0 {
+			continue
+		}
+		names := []string{p.Name, p.NameAscii}
+		if opts.NamesAlt {
+			names = append(names, p.NamesAlt...)
+		}
+		keys = keys[:0]
+		for _, name := range names {
+			if key := geonames_search.Fold(name); len(key) > 0 && !in(keys, key) {
+				if keys = append(keys, key); seen != nil {
+					seen[countryKey{p.Country.Code, key}] = true
+				}
+				me.Entries = append(me.Entries, Entry{Key: key, Name: p.Name, Match: name, Kind: KindPlace, Id: p.Id, Country: p.Country.Code,
+					Feature: p.Feature.Class + "." + p.Feature.Code, Population: p.Population, LonLat: p.LonLat})
+			}
+		}
+	}
+	if opts.Postals {
+		for _, p := range store.Postals {
+			if key := geonames_search.Fold(p.PlaceName); len(key) > 0 && !seen[countryKey{p.CountryCode, key}] {
+				seen[countryKey{p.CountryCode, key}] = true
+				me.Entries = append(me.Entries, Entry{Key: key, Name: p.PlaceName, Match: p.PlaceName, Kind: KindPostal, Id: int64(p.Ref), Country: p.CountryCode, LonLat: p.LonLat})
+			}
+		}
+	}
+	sort.Slice(me.Entries, func(i, j int) bool {
+		if me.Entries[i].Key == me.Entries[j].Key {
+			return me.Entries[i].Population > me.Entries[j].Population
+		}
+		return me.Entries[i].Key < me.Entries[j].Key
+	})
+	me.buildTree()
+	return
+}
+
+func (me *Index) buildTree() {
+	n := len(me.Entries)
+	me.maxPops = make([]int64, 2*n)
+	for i := range me.Entries {
+		me.maxPops[n+i] = me.Entries[i].Population
+	}
+	for i := n - 1; i > 0; i-- {
+		if me.maxPops[i] = me.maxPops[2*i]; me.maxPops[2*i+1] > me.maxPops[i] {
+			me.maxPops[i] = me.maxPops[2*i+1]
+		}
+	}
+}
+
+//	Returns up to `max` entries whose `Key` starts with the `geonames_search.Fold`ed `prefix`, most populated first. `filter` may be `nil`.
+//
+//	Each place or postal place name is returned at most once, even if several of its name variants match.
+func (me *Index) Complete(prefix string, max int, filter *Filter) (entries []*Entry) {
+	prefix = geonames_search.Fold(prefix)
+	if len(prefix) == 0 || max < 1 {
+		return
+	}
+	n := len(me.Entries)
+	lo := sort.Search(n, func(i int) bool { return me.Entries[i].Key >= prefix })
+	hi := lo + sort.Search(n-lo, func(i int) bool { return !strings.HasPrefix(me.Entries[lo+i].Key, prefix) })
+	var nodes nodeHeap
+	for l, r := lo+n, hi+n; l < r; l, r = l/2, r/2 {
+		if l&1 == 1 {
+			nodes = append(nodes, node{l, me.maxPops[l]})
+			l++
+		}
+		if r&1 == 1 {
+			r--
+			nodes = append(nodes, node{r, me.maxPops[r]})
+		}
+	}
+	heap.Init(&nodes)
+	seen := map[[2]int64]bool{}
+	for len(nodes) > 0 && len(entries) < max {
+		if nd := heap.Pop(&nodes).(node); nd.index >= n {
+			if e := &me.Entries[nd.index-n]; (filter == nil || filter.accepts(e)) && !seen[[2]int64{int64(e.Kind), e.Id}] {
+				seen[[2]int64{int64(e.Kind), e.Id}] = true
+				entries = append(entries, e)
+			}
+		} else {
+			heap.Push(&nodes, node{2 * nd.index, me.maxPops[2*nd.index]})
+			heap.Push(&nodes, node{2*nd.index + 1, me.maxPops[2*nd.index+1]})
+		}
+	}
+	return
+}
+
+type node struct {
+	index int
+	pop   int64
+}
+
+//	A max-heap of segment tree `node`s by `pop`.
+type nodeHeap []node
+
+func (me nodeHeap) Len() int { return len(me) }
+func (me nodeHeap) Less(i, j int) bool {
+	return me[i].pop > me[j].pop || (me[i].pop == me[j].pop && me[i].index < me[j].index)
+}
+func (me nodeHeap) Swap(i, j int)       { me[i], me[j] = me[j], me[i] }
+func (me *nodeHeap) Push(x interface{}) { *me = append(*me, x.(node)) }
+func (me *nodeHeap) Pop() (x interface{}) {
+	old := *me
+	x, *me = old[len(old)-1], old[:len(old)-1]
+	return
+}
+
+//	Writes `me` to the specified file, to be read back via `Load`.
+func (me *Index) Save(filePath string) (err error) {
+	var file *os.File
+	if file, err = os.Create(filePath); err == nil {
+		buf := bufio.NewWriter(file)
+		enc := gob.NewEncoder(buf)
+		if err = enc.Encode(FormatVersion); err == nil {
+			if err = enc.Encode(me.Entries); err == nil {
+				err = buf.Flush()
+			}
+		}
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return
+}
+
+//	Reads an `Index` previously written via `Index.Save`.
+func Load(filePath string) (me *Index, err error) {
+	var file *os.File
+	if file, err = os.Open(filePath); err == nil {
+		defer file.Close()
+		var version int
+		dec := gob.NewDecoder(bufio.NewReader(file))
+		if err = dec.Decode(&version); err == nil {
+			if version != FormatVersion {
+				err = fmt.Errorf("%s: format version %d, expected %d", filePath, version, FormatVersion)
+			} else {
+				me = &Index{}
+				if err = dec.Decode(&me.Entries); err == nil {
+					me.buildTree()
+				} else {
+					me = nil
+				}
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,170 @@
+//	A compact, versioned binary snapshot of parsed `download.geonames.org/export/dump` records (via `parse-dumps` package),
+//	with sorted fixed-width records, a shared string table and `spatial-index` trees, that is memory-mapped and queried in place.
+//
+//	A snapshot file starts with a header (magic bytes, format version, creation time and section count), followed by a table of
+//	sections (kind, record size, record count, byte offset and length), followed by the 8-byte-aligned section contents.
+//	All numbers are little-endian. Strings are `(offset, length)` references into the string table section.
+package geonames_snapshot
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+	"unsafe"
+)
+
+const (
+	//	Format version written by `Write`. `Open` accepts files of the same major version only, and of any minor version:
+	//	minor versions may only add section kinds, which older readers ignore.
+	FormatMajor = 1
+	FormatMinor = 0
+
+	//	Identifies snapshot files
+	Magic = "GEOSNAP\x00"
+)
+
+//	Returned by `ReadVersion` and `Open` for files that do not start with `Magic`.
+var ErrNotSnapshot = errors.New("not a geonames snapshot file")
+
+const (
+	secStrings uint32 = iota + 1
+	secCountries
+	secTimezones
+	secFeatures
+	secAdmins
+	secPlaces
+	secPlaceTreePoints
+	secPlaceTreeItems
+	secPlaceTreeAxes
+	secPostals
+	secPostalTreePoints
+	secPostalTreeItems
+	secPostalTreeAxes
+)
+
+type header struct {
+	Magic        [8]byte
+	Major, Minor uint16
+	NumSections  uint32
+	Created      int64
+}
+
+type section struct {
+	Kind    uint32
+	RecSize uint32
+	Count   uint64
+	Offset  uint64
+	Length  uint64
+}
+
+//	A string in the string table.
+type strRef struct {
+	Off, Len uint32
+}
+
+//	The fixed-width records below have no implicit padding, so that their in-memory layout equals their file layout.
+
+type countryRec struct {
+	Id, AreaSqKm, Population                                int64
+	Iso2, Iso3, IsoNum, Fips, Name, Capital, Continent, Tld strRef
+	CurrencyCode, CurrencyName, CallingCode, PostalFormat   strRef
+	PostalRegex, Languages, Neighbors                       strRef
+}
+
+type timezoneRec struct {
+	OffsetGmt, OffsetDst, OffsetRaw float64
+	CountryCode, TimezoneName       strRef
+}
+
+type featureRec struct {
+	Code, Name, Desc strRef
+}
+
+type adminRec struct {
+	Id                    int64
+	Code, Name, NameAscii strRef
+}
+
+const (
+	flagLonLat uint8 = 1 << iota
+)
+
+//	`Modified` holds days since 1970-01-01, or `noDate`.
+type placeRec struct {
+	Id, Population                      int64
+	Name, NameAscii, NamesAlt, CodesAlt strRef
+	Admin                               [4]strRef
+	Timezone                            strRef
+	Lon, Lat                            float64
+	Elevation, Modified                 int32
+	FeatureClass                        [1]byte
+	FeatureCode                         [7]byte
+	Country                             [2]byte
+	Flags                               uint8
+	_                                   [5]byte
+}
+
+type postalRec struct {
+	Lon, Lat             float64
+	Code, PlaceName      strRef
+	AdminName, AdminCode [3]strRef
+	Accuracy             int32
+	Country              [2]byte
+	Flags                uint8
+	_                    [1]byte
+}
+
+const noDate = -1 << 31
+
+//	Reads the format version of the snapshot file at `filePath`, failing with `ErrNotSnapshot` if it is none.
+func ReadVersion(filePath string) (major, minor int, err error) {
+	var file *os.File
+	if file, err = os.Open(filePath); err == nil {
+		defer file.Close()
+		var hdr header
+		if hdr, err = readHeader(file); err == nil {
+			major, minor = int(hdr.Major), int(hdr.Minor)
+		}
+	}
+	return
+}
+
+//	Returns an error unless files of the specified format version can be read by this package (see `FormatMajor`).
+func Compatible(major, minor int) error {
+	if major != FormatMajor {
+		return fmt.Errorf("incompatible snapshot format version %d.%d, expected %d.x", major, minor, FormatMajor)
+	}
+	return nil
+}
+
+func readHeader(r io.Reader) (hdr header, err error) {
+	if err = binary.Read(r, binary.LittleEndian, &hdr); err == io.EOF || err == io.ErrUnexpectedEOF || (err == nil && string(hdr.Magic[:]) != Magic) {
+		err = ErrNotSnapshot
+	}
+	return
+}
+
+func date(t time.Time) int32 {
+	if t.IsZero() {
+		return noDate
+	}
+	return int32(t.Unix() / 86400)
+}
+
+func unfixed(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
+
+//	Whether the in-memory layout of records equals their (little-endian) file layout, allowing zero-copy access.
+var littleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package geonames_snapshot
+
+import (
+	"os"
+)
+
+//	Reads the whole file into memory on platforms without `syscall.Mmap`.
+func mmap(filePath string) (data []byte, unmap func() error, err error) {
+	if data, err = os.ReadFile(filePath); err == nil {
+		unmap = func() error { return nil }
+	}
+	return
+}
@@ -0,0 +1,27 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package geonames_snapshot
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+func mmap(filePath string) (data []byte, unmap func() error, err error) {
+	var file *os.File
+	if file, err = os.Open(filePath); err == nil {
+		defer file.Close()
+		var info os.FileInfo
+		if info, err = file.Stat(); err == nil {
+			if size := info.Size(); size == 0 {
+				unmap = func() error { return nil }
+			} else if int64(int(size)) != size {
+				err = fmt.Errorf("%s: too large to map (%d bytes)", filePath, size)
+			} else if data, err = syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED); err == nil {
+				unmap = func() error { return syscall.Munmap(data) }
+			}
+		}
+	}
+	return
+}
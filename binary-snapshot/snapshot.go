@@ -0,0 +1,347 @@
+package geonames_snapshot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/go-geo/geonames/parse-dumps"
+	"github.com/go-geo/geonames/spatial-index"
+)
+
+//	A snapshot file opened by `Open`. Records are decoded from the mapped file on each lookup, so every returned record is a fresh copy.
+//	All methods are safe for concurrent use until `Close`.
+type Snapshot struct {
+	//	Format version of the file
+	Major, Minor int
+
+	//	When the file was written
+	Created time.Time
+
+	data  []byte
+	unmap func() error
+
+	strs       []byte
+	countries  []countryRec
+	timezones  []timezoneRec
+	features   []featureRec
+	admins     []adminRec
+	places     []placeRec
+	postals    []postalRec
+	placeTree  geonames_spatial.Tree
+	postalTree geonames_spatial.Tree
+}
+
+//	A result of `NearestPlaces`.
+type PlaceResult struct {
+	Place *geonames_parse.PlaceRec
+
+	//	Great-circle distance in kilometers from the queried coordinates
+	DistanceKm float64
+}
+
+//	A result of `NearestPostals`.
+type PostalResult struct {
+	Postal *geonames_parse.PostalRec
+
+	//	Great-circle distance in kilometers from the queried coordinates
+	DistanceKm float64
+}
+
+//	Memory-maps the snapshot file at `filePath` (or reads it into memory where `mmap` is unavailable) and checks its format version
+//	(see `Compatible`). Opening does not decode any records, but checks that the `spatial-index` trees refer to existing ones.
+func Open(filePath string) (me *Snapshot, err error) {
+	me = &Snapshot{}
+	if me.data, me.unmap, err = mmap(filePath); err == nil {
+		if err = me.load(); err != nil {
+			me.Close()
+		}
+	}
+	if err != nil {
+		me = nil
+	}
+	return
+}
+
+func (me *Snapshot) load() (err error) {
+	var hdr header
+	r := bytes.NewReader(me.data)
+	if hdr, err = readHeader(r); err == nil {
+		me.Major, me.Minor, me.Created = int(hdr.Major), int(hdr.Minor), time.Unix(hdr.Created, 0)
+		if err = Compatible(me.Major, me.Minor); err == nil {
+			if int64(hdr.NumSections)*int64(binary.Size(section{})) > int64(r.Len()) {
+				return fmt.Errorf("snapshot truncated: %d sections", hdr.NumSections)
+			}
+			sections := make([]section, hdr.NumSections)
+			if err = binary.Read(r, binary.LittleEndian, sections); err == nil {
+				for i := 0; i < len(sections) && err == nil; i++ {
+					err = me.loadSection(&sections[i])
+				}
+			}
+			if err == nil {
+				if err = checkTree("place", &me.placeTree, len(me.places)); err == nil {
+					err = checkTree("postal", &me.postalTree, len(me.postals))
+				}
+			}
+		}
+	}
+	return
+}
+
+func (me *Snapshot) loadSection(sec *section) (err error) {
+	switch sec.Kind {
+	case secStrings:
+		me.strs, err = view[byte](me.data, sec)
+	case secCountries:
+		me.countries, err = view[countryRec](me.data, sec)
+	case secTimezones:
+		me.timezones, err = view[timezoneRec](me.data, sec)
+	case secFeatures:
+		me.features, err = view[featureRec](me.data, sec)
+	case secAdmins:
+		me.admins, err = view[adminRec](me.data, sec)
+	case secPlaces:
+		me.places, err = view[placeRec](me.data, sec)
+	case secPlaceTreePoints:
+		me.placeTree.Points, err = view[geonames_spatial.Point](me.data, sec)
+	case secPlaceTreeItems:
+		me.placeTree.Items, err = view[int32](me.data, sec)
+	case secPlaceTreeAxes:
+		me.placeTree.Axes, err = view[uint8](me.data, sec)
+	case secPostals:
+		me.postals, err = view[postalRec](me.data, sec)
+	case secPostalTreePoints:
+		me.postalTree.Points, err = view[geonames_spatial.Point](me.data, sec)
+	case secPostalTreeItems:
+		me.postalTree.Items, err = view[int32](me.data, sec)
+	case secPostalTreeAxes:
+		me.postalTree.Axes, err = view[uint8](me.data, sec)
+	}
+	return
+}
+
+//	Returns the records of `sec` in `data`: in place if their layout allows, else decoded into a new slice.
+func view[T any](data []byte, sec *section) (recs []T, err error) {
+	var zero T
+	size := binary.Size(zero)
+	if int(sec.RecSize) != size {
+		return nil, fmt.Errorf("snapshot section %d: record size %d, expected %d", sec.Kind, sec.RecSize, size)
+	} else if sec.Count > sec.Length/uint64(size) || sec.Length != sec.Count*uint64(size) || sec.Offset > uint64(len(data)) || sec.Length > uint64(len(data))-sec.Offset {
+		return nil, fmt.Errorf("snapshot section %d truncated", sec.Kind)
+	} else if sec.Count == 0 {
+		return
+	}
+	b := data[sec.Offset : sec.Offset+sec.Length]
+	if littleEndian && int(unsafe.Sizeof(zero)) == size && uintptr(unsafe.Pointer(&b[0]))%unsafe.Alignof(zero) == 0 {
+		recs = unsafe.Slice((*T)(unsafe.Pointer(&b[0])), sec.Count)
+	} else {
+		recs = make([]T, sec.Count)
+		err = binary.Read(bytes.NewReader(b), binary.LittleEndian, recs)
+	}
+	return
+}
+
+//	Checks that `tree` has as many items and axes as points, and that all items are indices of `count` records.
+func checkTree(name string, tree *geonames_spatial.Tree, count int) error {
+	if len(tree.Items) != len(tree.Points) || len(tree.Axes) != len(tree.Points) {
+		return fmt.Errorf("snapshot %s tree: %d points, %d items and %d axes", name, len(tree.Points), len(tree.Items), len(tree.Axes))
+	}
+	for i, item := range tree.Items {
+		if item < 0 || int(item) >= count || tree.Axes[i] > 2 {
+			return fmt.Errorf("snapshot %s tree: node %d refers to record %d (of %d) along axis %d", name, i, item, count, tree.Axes[i])
+		}
+	}
+	return nil
+}
+
+//	Unmaps the file. No other methods may be called afterwards, but records returned earlier remain valid.
+func (me *Snapshot) Close() (err error) {
+	if me.unmap != nil {
+		err, me.unmap = me.unmap(), nil
+	}
+	me.data, me.strs, me.countries, me.timezones, me.features, me.admins, me.places, me.postals = nil, nil, nil, nil, nil, nil, nil, nil
+	me.placeTree, me.postalTree = geonames_spatial.Tree{}, geonames_spatial.Tree{}
+	return
+}
+
+//	Returns the `AdminRec` with the specified full code (eg. `US.CA.037`), or `nil`.
+func (me *Snapshot) AdminByCode(code string) *geonames_parse.AdminRec {
+	if i := search(len(me.admins), func(i int) int { return strings.Compare(me.raw(me.admins[i].Code), code) }); i >= 0 {
+		a := &me.admins[i]
+		return &geonames_parse.AdminRec{Id: a.Id, Code: me.str(a.Code), Name: me.str(a.Name), NameAscii: me.str(a.NameAscii)}
+	}
+	return nil
+}
+
+//	Returns all countries, ordered by their ISO 3166-1 alpha-2 code.
+func (me *Snapshot) Countries() (countries []*geonames_parse.CountryRec) {
+	countries = make([]*geonames_parse.CountryRec, len(me.countries))
+	for i := range me.countries {
+		countries[i] = me.country(&me.countries[i])
+	}
+	return
+}
+
+//	Returns the `CountryRec` with the specified ISO 3166-1 alpha-2 code (eg. `DE`), or `nil`.
+func (me *Snapshot) CountryByCode(iso2 string) *geonames_parse.CountryRec {
+	if i := search(len(me.countries), func(i int) int { return strings.Compare(me.raw(me.countries[i].Iso2), iso2) }); i >= 0 {
+		return me.country(&me.countries[i])
+	}
+	return nil
+}
+
+func (me *Snapshot) country(c *countryRec) (r *geonames_parse.CountryRec) {
+	r = &geonames_parse.CountryRec{Id: c.Id, AreaSqKm: c.AreaSqKm, Population: c.Population, Name: me.str(c.Name), Capital: me.str(c.Capital),
+		Continent: me.str(c.Continent), Tld: me.str(c.Tld), CallingCode: me.str(c.CallingCode), Languages: me.list(c.Languages), Neighbors: me.list(c.Neighbors)}
+	r.Code.Iso2, r.Code.Iso3, r.Code.IsoNum, r.Code.Fips = me.str(c.Iso2), me.str(c.Iso3), me.str(c.IsoNum), me.str(c.Fips)
+	r.Currency.Code, r.Currency.Name = me.str(c.CurrencyCode), me.str(c.CurrencyName)
+	r.PostalCode.Format, r.PostalCode.Regex = me.str(c.PostalFormat), me.str(c.PostalRegex)
+	return
+}
+
+//	Returns the `FeatureRec` with the specified code (eg. `P.PPLC`), or `nil`.
+func (me *Snapshot) FeatureByCode(code string) *geonames_parse.FeatureRec {
+	if i := search(len(me.features), func(i int) int { return strings.Compare(me.raw(me.features[i].Code), code) }); i >= 0 {
+		f := &me.features[i]
+		return &geonames_parse.FeatureRec{Code: me.str(f.Code), Name: me.str(f.Name), Desc: me.str(f.Desc)}
+	}
+	return nil
+}
+
+//	Returns the `TimezoneRec` with the specified IANA name (eg. `Europe/Berlin`), or `nil`.
+func (me *Snapshot) TimezoneByName(name string) *geonames_parse.TimezoneRec {
+	if i := search(len(me.timezones), func(i int) int { return strings.Compare(me.raw(me.timezones[i].TimezoneName), name) }); i >= 0 {
+		t := &me.timezones[i]
+		return &geonames_parse.TimezoneRec{CountryCode: me.str(t.CountryCode), TimezoneName: me.str(t.TimezoneName),
+			OffsetGmt: t.OffsetGmt, OffsetDst: t.OffsetDst, OffsetRaw: t.OffsetRaw}
+	}
+	return nil
+}
+
+//	Returns the number of places, which are ordered by their geo-name ID.
+func (me *Snapshot) NumPlaces() int {
+	return len(me.places)
+}
+
+//	Returns the place at `index` (`0 <= index < NumPlaces()`).
+func (me *Snapshot) PlaceAt(index int) *geonames_parse.PlaceRec {
+	return me.place(&me.places[index])
+}
+
+//	Returns the place with the specified geo-name `id`, or `nil`.
+func (me *Snapshot) Place(id int64) *geonames_parse.PlaceRec {
+	if i := sort.Search(len(me.places), func(i int) bool { return me.places[i].Id >= id }); i < len(me.places) && me.places[i].Id == id {
+		return me.place(&me.places[i])
+	}
+	return nil
+}
+
+//	Returns up to `k` places nearest to `lon`/`lat` (in degrees), nearest first. Only places within `maxKm` (if `> 0`) and satisfying
+//	`accept` (if not `nil`) are considered.
+func (me *Snapshot) NearestPlaces(lon, lat float64, k int, maxKm float64, accept func(*geonames_parse.PlaceRec) bool) (results []PlaceResult) {
+	var filter func(int) bool
+	if accept != nil {
+		filter = func(i int) bool { return accept(me.place(&me.places[i])) }
+	}
+	nearest := me.placeTree.Nearest(lon, lat, k, maxKm, filter)
+	results = make([]PlaceResult, len(nearest))
+	for i, n := range nearest {
+		results[i] = PlaceResult{Place: me.place(&me.places[n.Item]), DistanceKm: n.DistanceKm}
+	}
+	return
+}
+
+func (me *Snapshot) place(p *placeRec) (r *geonames_parse.PlaceRec) {
+	r = &geonames_parse.PlaceRec{Id: p.Id, Name: me.str(p.Name), NameAscii: me.str(p.NameAscii), NamesAlt: me.list(p.NamesAlt),
+		Population: p.Population, Elevation: int64(p.Elevation), TimezoneName: me.str(p.Timezone)}
+	if p.Flags&flagLonLat != 0 {
+		r.LonLat = []float64{p.Lon, p.Lat}
+	}
+	r.Feature.Class, r.Feature.Code = unfixed(p.FeatureClass[:]), unfixed(p.FeatureCode[:])
+	r.Country.Code, r.Country.CodesAlt = unfixed(p.Country[:]), me.list(p.CodesAlt)
+	r.Admin.Code1, r.Admin.Code2, r.Admin.Code3, r.Admin.Code4 = me.str(p.Admin[0]), me.str(p.Admin[1]), me.str(p.Admin[2]), me.str(p.Admin[3])
+	if p.Modified != noDate {
+		r.Modified = time.Unix(int64(p.Modified)*86400, 0).UTC()
+	}
+	return
+}
+
+//	Returns the number of postal codes, which are ordered by country and postal code.
+func (me *Snapshot) NumPostals() int {
+	return len(me.postals)
+}
+
+//	Returns the postal code at `index` (`0 <= index < NumPostals()`).
+func (me *Snapshot) PostalAt(index int) *geonames_parse.PostalRec {
+	return me.postal(&me.postals[index])
+}
+
+//	Returns all places with the specified `postalCode` in the specified country (eg. `DE`, `80331`).
+func (me *Snapshot) PostalCodes(country, postalCode string) (postals []*geonames_parse.PostalRec) {
+	cmp := func(i int) int {
+		if c := strings.Compare(unsafe.String(&me.postals[i].Country[0], 2), country); c != 0 {
+			return c
+		}
+		return strings.Compare(me.raw(me.postals[i].Code), postalCode)
+	}
+	for i := sort.Search(len(me.postals), func(i int) bool { return cmp(i) >= 0 }); i < len(me.postals) && cmp(i) == 0; i++ {
+		postals = append(postals, me.postal(&me.postals[i]))
+	}
+	return
+}
+
+//	Returns up to `k` postal codes nearest to `lon`/`lat` (in degrees), nearest first. Only postal codes within `maxKm` (if `> 0`)
+//	and satisfying `accept` (if not `nil`) are considered.
+func (me *Snapshot) NearestPostals(lon, lat float64, k int, maxKm float64, accept func(*geonames_parse.PostalRec) bool) (results []PostalResult) {
+	var filter func(int) bool
+	if accept != nil {
+		filter = func(i int) bool { return accept(me.postal(&me.postals[i])) }
+	}
+	nearest := me.postalTree.Nearest(lon, lat, k, maxKm, filter)
+	results = make([]PostalResult, len(nearest))
+	for i, n := range nearest {
+		results[i] = PostalResult{Postal: me.postal(&me.postals[n.Item]), DistanceKm: n.DistanceKm}
+	}
+	return
+}
+
+func (me *Snapshot) postal(p *postalRec) (r *geonames_parse.PostalRec) {
+	r = &geonames_parse.PostalRec{CountryCode: unfixed(p.Country[:]), PostalCode: me.str(p.Code), PlaceName: me.str(p.PlaceName), Accuracy: int64(p.Accuracy)}
+	r.Admin.Name1, r.Admin.Name2, r.Admin.Name3 = me.str(p.AdminName[0]), me.str(p.AdminName[1]), me.str(p.AdminName[2])
+	r.Admin.Code1, r.Admin.Code2, r.Admin.Code3 = me.str(p.AdminCode[0]), me.str(p.AdminCode[1]), me.str(p.AdminCode[2])
+	if p.Flags&flagLonLat != 0 {
+		r.LonLat = []float64{p.Lon, p.Lat}
+	}
+	return
+}
+
+//	Returns the string table bytes of `ref` as a string without copying, valid only until `Close`.
+func (me *Snapshot) raw(ref strRef) string {
+	if ref.Len == 0 || uint64(ref.Off)+uint64(ref.Len) > uint64(len(me.strs)) {
+		return ""
+	}
+	return unsafe.String(&me.strs[ref.Off], int(ref.Len))
+}
+
+func (me *Snapshot) str(ref strRef) string {
+	return strings.Clone(me.raw(ref))
+}
+
+func (me *Snapshot) list(ref strRef) []string {
+	if s := me.str(ref); len(s) > 0 {
+		return strings.Split(s, ",")
+	}
+	return nil
+}
+
+//	Returns the index in `[0, n)` for which `cmp` returns `0`, or `-1`, where `cmp` is ascending over the index.
+func search(n int, cmp func(int) int) int {
+	if i := sort.Search(n, func(i int) bool { return cmp(i) >= 0 }); i < n && cmp(i) == 0 {
+		return i
+	}
+	return -1
+}
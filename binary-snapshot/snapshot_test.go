@@ -0,0 +1,174 @@
+package geonames_snapshot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+const testdata = "../parse-dumps/testdata"
+
+//	Writes a snapshot of the `parse-dumps` testdata into a new temporary directory.
+func writeTestdata(t *testing.T, opts *Options) (filePath string) {
+	filePath = filepath.Join(t.TempDir(), "geonames.snap")
+	if err := Write(geonames_parse.NewIterator(testdata), filePath, opts); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func open(t *testing.T, filePath string) *Snapshot {
+	snap, err := Open(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { snap.Close() })
+	return snap
+}
+
+//	Returns `r` as written by `write`, or `"<nil>"`.
+func line[R any](r *R, write func(*geonames_parse.Writer, *R) error) string {
+	if r == nil {
+		return "<nil>"
+	}
+	var buf bytes.Buffer
+	w := geonames_parse.NewWriter(&buf)
+	if write(w, r) != nil || w.Flush() != nil {
+		return "<error>"
+	}
+	return buf.String()
+}
+
+func TestWriteOpen(t *testing.T) {
+	snap := open(t, writeTestdata(t, &Options{AlternateNames: true}))
+	if snap.Major != FormatMajor || snap.Minor != FormatMinor {
+		t.Errorf("version %d.%d", snap.Major, snap.Minor)
+	}
+
+	geo := geonames_parse.NewIterator(testdata)
+	var places []geonames_parse.PlaceRec
+	if err := geo.Places(func(_ int, r *geonames_parse.PlaceRec) { places = append(places, *r) }); err != nil {
+		t.Fatal(err)
+	}
+	if snap.NumPlaces() != len(places) {
+		t.Fatalf("%d places, expected %d", snap.NumPlaces(), len(places))
+	}
+	for i := range places {
+		expected := places[i]
+		expected.Dem = 0 // not stored
+		if place, want := line(snap.Place(expected.Id), (*geonames_parse.Writer).Place), line(&expected, (*geonames_parse.Writer).Place); place != want {
+			t.Errorf("Place(%d):\n%s\nexpected\n%s", expected.Id, place, want)
+		}
+	}
+	if err := geo.Countries(func(_ int, r *geonames_parse.CountryRec) {
+		expected := *r
+		expected.EquivalentFipsCode = "" // not stored
+		if country, want := line(snap.CountryByCode(r.Code.Iso2), (*geonames_parse.Writer).Country), line(&expected, (*geonames_parse.Writer).Country); country != want {
+			t.Errorf("CountryByCode(%q):\n%s\nexpected\n%s", r.Code.Iso2, country, want)
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := geo.PostalCodes(func(_ int, r *geonames_parse.PostalRec) {
+		if postals := snap.PostalCodes(r.CountryCode, r.PostalCode); len(postals) != 1 || line(postals[0], (*geonames_parse.Writer).Postal) != line(r, (*geonames_parse.Writer).Postal) {
+			t.Errorf("PostalCodes(%q, %q): %+v, expected %+v", r.CountryCode, r.PostalCode, postals, *r)
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if admin := snap.AdminByCode("DE.02.091"); admin == nil || admin.Name != "Oberbayern" {
+		t.Errorf("AdminByCode: %+v", admin)
+	}
+	if tz := snap.TimezoneByName("Asia/Kathmandu"); tz == nil || tz.OffsetGmt != 5.75 {
+		t.Errorf("TimezoneByName: %+v", tz)
+	}
+	if nearest := snap.NearestPlaces(11.6, 48.1, 2, 0, nil); len(nearest) != 2 || nearest[0].Place.Id != 2867714 || nearest[1].Place.Id != 2960313 {
+		t.Errorf("NearestPlaces: %+v", nearest)
+	}
+	if nearest := snap.NearestPostals(-89.6, 39.8, 5, 0, nil); len(nearest) != 2 || nearest[0].Postal.PostalCode != "62701" {
+		t.Errorf("NearestPostals: %+v", nearest) // `AD100` lacks coordinates
+	}
+}
+
+func TestWriteWhileOpen(t *testing.T) {
+	filePath := writeTestdata(t, nil)
+	snap := open(t, filePath)
+	if err := Write(geonames_parse.NewIterator(testdata), filePath, &Options{SkipPostals: true}); err != nil {
+		t.Fatal(err)
+	}
+	if postals := snap.PostalCodes("US", "62701"); snap.NumPostals() != 3 || len(postals) != 1 { // the last records of the file
+		t.Errorf("postals of the replaced snapshot: %d, %+v", snap.NumPostals(), postals)
+	}
+	if again := open(t, filePath); again.NumPostals() != 0 || again.NumPlaces() != 4 {
+		t.Errorf("rewritten snapshot: %d places, %d postals", again.NumPlaces(), again.NumPostals())
+	}
+	if matches, _ := filepath.Glob(filePath + ".*"); len(matches) > 0 {
+		t.Errorf("temporary files left: %v", matches)
+	}
+}
+
+//	Writes `data` as a new file and expects `Open` to fail on it.
+func openFails(t *testing.T, what string, data []byte) {
+	filePath := filepath.Join(t.TempDir(), "broken.snap")
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if snap, err := Open(filePath); err == nil {
+		snap.Close()
+		t.Errorf("%s: opened without error", what)
+	}
+}
+
+func TestOpenTruncated(t *testing.T) {
+	data, err := os.ReadFile(writeTestdata(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, size := range []int{0, 7, binary.Size(header{}), binary.Size(header{}) + 10, len(data) / 2, len(data) - 1} {
+		openFails(t, "truncated", data[:size])
+	}
+}
+
+func TestOpenMajorVersion(t *testing.T) {
+	filePath := writeTestdata(t, nil)
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binary.LittleEndian.PutUint16(data[8:], FormatMajor+1)
+	openFails(t, "major version", data)
+
+	binary.LittleEndian.PutUint16(data[8:], FormatMajor)
+	binary.LittleEndian.PutUint16(data[10:], FormatMinor+1)
+	if err = os.WriteFile(filePath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if snap := open(t, filePath); snap.Minor != FormatMinor+1 {
+		t.Errorf("minor version %d", snap.Minor)
+	}
+}
+
+func TestOpenCorruptTree(t *testing.T) {
+	data, err := os.ReadFile(writeTestdata(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hdr, sections := binary.Size(header{}), binary.Size(section{})
+	for i := 0; i < int(binary.LittleEndian.Uint32(data[12:])); i++ {
+		sec := data[hdr+i*sections:]
+		if binary.LittleEndian.Uint32(sec) == secPlaceTreeItems {
+			broken := append([]byte{}, data...)
+			binary.LittleEndian.PutUint32(broken[binary.LittleEndian.Uint64(sec[16:]):], 4) // only places 0..3 exist
+			openFails(t, "tree item out of range", broken)
+
+			broken = append([]byte{}, data...)
+			count := binary.LittleEndian.Uint64(sec[8:]) + 1<<62 // times the 4-byte record size, wraps around to the same length
+			binary.LittleEndian.PutUint64(broken[hdr+i*sections+8:], count)
+			openFails(t, "overflowing count", broken)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package geonames_snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/go-geo/geonames/parse-dumps"
+	"github.com/go-geo/geonames/spatial-index"
+)
+
+//	Controls `Write`.
+type Options struct {
+	//	Whether to include `PlaceRec.NamesAlt` (which roughly doubles the string table)
+	AlternateNames bool
+
+	//	Whether to leave out the postal codes
+	SkipPostals bool
+}
+
+//	Reads all countries, time zones, features, admin divisions, places and (unless `opts.SkipPostals`) postal codes from `geo`
+//	and writes them as a snapshot file to `filePath`. Files that do not exist in `geo.DirPath` result in empty sections.
+//
+//	All records are held in memory until written, as fixed-width records (about 128 bytes per place) plus their distinct strings.
+//	The file is written under a temporary name next to `filePath` and then renamed, so that an existing snapshot at `filePath`
+//	remains intact for any `Snapshot` still mapping it.
+func Write(geo *geonames_parse.Iterator, filePath string, opts *Options) (err error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	me := &writer{opts: opts, refs: map[string]strRef{}}
+	if err = me.read(geo); err == nil {
+		if len(me.strs) > math.MaxUint32 {
+			return fmt.Errorf("string table exceeds 4 GiB")
+		}
+		me.sort()
+		var file *os.File
+		if file, err = os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp"); err == nil {
+			w := bufio.NewWriterSize(file, 1<<20)
+			if err = me.write(w); err == nil {
+				if err = w.Flush(); err == nil {
+					err = file.Chmod(0644)
+				}
+			}
+			if cerr := file.Close(); err == nil {
+				err = cerr
+			}
+			if err == nil {
+				err = os.Rename(file.Name(), filePath)
+			}
+			if err != nil {
+				os.Remove(file.Name())
+			}
+		}
+	}
+	return
+}
+
+type writer struct {
+	opts *Options
+	strs []byte
+	refs map[string]strRef
+
+	countries []countryRec
+	timezones []timezoneRec
+	features  []featureRec
+	admins    []adminRec
+	places    []placeRec
+	postals   []postalRec
+}
+
+//	Returns the string table reference of `s`, adding `s` if new.
+func (me *writer) ref(s string) (ref strRef) {
+	if len(s) > 0 {
+		var ok bool
+		if ref, ok = me.refs[s]; !ok {
+			ref = strRef{Off: uint32(len(me.strs)), Len: uint32(len(s))}
+			me.strs, me.refs[s] = append(me.strs, s...), ref
+		}
+	}
+	return
+}
+
+func (me *writer) bytes(ref strRef) []byte {
+	return me.strs[ref.Off : ref.Off+ref.Len]
+}
+
+func (me *writer) read(geo *geonames_parse.Iterator) (err error) {
+	steps := []func() error{
+		func() error {
+			return geo.Countries(func(_ int, r *geonames_parse.CountryRec) {
+				me.countries = append(me.countries, countryRec{Id: r.Id, AreaSqKm: r.AreaSqKm, Population: r.Population,
+					Iso2: me.ref(r.Code.Iso2), Iso3: me.ref(r.Code.Iso3), IsoNum: me.ref(r.Code.IsoNum), Fips: me.ref(r.Code.Fips),
+					Name: me.ref(r.Name), Capital: me.ref(r.Capital), Continent: me.ref(r.Continent), Tld: me.ref(r.Tld),
+					CurrencyCode: me.ref(r.Currency.Code), CurrencyName: me.ref(r.Currency.Name), CallingCode: me.ref(r.CallingCode),
+					PostalFormat: me.ref(r.PostalCode.Format), PostalRegex: me.ref(r.PostalCode.Regex),
+					Languages: me.ref(strings.Join(r.Languages, ",")), Neighbors: me.ref(strings.Join(r.Neighbors, ","))})
+			})
+		},
+		func() error {
+			return geo.Timezones(func(_ int, r *geonames_parse.TimezoneRec) {
+				me.timezones = append(me.timezones, timezoneRec{OffsetGmt: r.OffsetGmt, OffsetDst: r.OffsetDst, OffsetRaw: r.OffsetRaw,
+					CountryCode: me.ref(r.CountryCode), TimezoneName: me.ref(r.TimezoneName)})
+			})
+		},
+		func() error {
+			return geo.Features(func(_ int, r *geonames_parse.FeatureRec) {
+				me.features = append(me.features, featureRec{Code: me.ref(r.Code), Name: me.ref(r.Name), Desc: me.ref(r.Desc)})
+			})
+		},
+		func() error {
+			return geo.AdminAll(func(_ int, r *geonames_parse.AdminRec) {
+				me.admins = append(me.admins, adminRec{Id: r.Id, Code: me.ref(r.Code), Name: me.ref(r.Name), NameAscii: me.ref(r.NameAscii)})
+			})
+		},
+		func() error {
+			return geo.Places(func(_ int, r *geonames_parse.PlaceRec) {
+				p := placeRec{Id: r.Id, Population: r.Population, Name: me.ref(r.Name), NameAscii: me.ref(r.NameAscii),
+					CodesAlt: me.ref(strings.Join(r.Country.CodesAlt, ",")), Timezone: me.ref(r.TimezoneName),
+					Elevation: int32(r.Elevation), Modified: date(r.Modified)}
+				if me.opts.AlternateNames {
+					p.NamesAlt = me.ref(strings.Join(r.NamesAlt, ","))
+				}
+				for i, code := range []string{r.Admin.Code1, r.Admin.Code2, r.Admin.Code3, r.Admin.Code4} {
+					p.Admin[i] = me.ref(code)
+				}
+				if len(r.LonLat) == 2 {
+					p.Lon, p.Lat, p.Flags = r.LonLat[0], r.LonLat[1], p.Flags|flagLonLat
+				}
+				copy(p.FeatureClass[:], r.Feature.Class)
+				copy(p.FeatureCode[:], r.Feature.Code)
+				copy(p.Country[:], r.Country.Code)
+				me.places = append(me.places, p)
+			})
+		},
+	}
+	if !me.opts.SkipPostals {
+		steps = append(steps, func() error {
+			return geo.PostalCodes(func(_ int, r *geonames_parse.PostalRec) {
+				p := postalRec{Code: me.ref(r.PostalCode), PlaceName: me.ref(r.PlaceName), Accuracy: int32(r.Accuracy),
+					AdminName: [3]strRef{me.ref(r.Admin.Name1), me.ref(r.Admin.Name2), me.ref(r.Admin.Name3)},
+					AdminCode: [3]strRef{me.ref(r.Admin.Code1), me.ref(r.Admin.Code2), me.ref(r.Admin.Code3)}}
+				if len(r.LonLat) == 2 {
+					p.Lon, p.Lat, p.Flags = r.LonLat[0], r.LonLat[1], p.Flags|flagLonLat
+				}
+				copy(p.Country[:], r.CountryCode)
+				me.postals = append(me.postals, p)
+			})
+		})
+	}
+	for _, step := range steps {
+		if err = step(); os.IsNotExist(err) {
+			err = nil
+		} else if err != nil {
+			break
+		}
+	}
+	return
+}
+
+//	Sorts all records by their lookup keys, as expected by `Snapshot`.
+func (me *writer) sort() {
+	sort.SliceStable(me.countries, func(i, j int) bool {
+		return bytes.Compare(me.bytes(me.countries[i].Iso2), me.bytes(me.countries[j].Iso2)) < 0
+	})
+	sort.SliceStable(me.timezones, func(i, j int) bool {
+		return bytes.Compare(me.bytes(me.timezones[i].TimezoneName), me.bytes(me.timezones[j].TimezoneName)) < 0
+	})
+	sort.SliceStable(me.features, func(i, j int) bool {
+		return bytes.Compare(me.bytes(me.features[i].Code), me.bytes(me.features[j].Code)) < 0
+	})
+	sort.SliceStable(me.admins, func(i, j int) bool {
+		return bytes.Compare(me.bytes(me.admins[i].Code), me.bytes(me.admins[j].Code)) < 0
+	})
+	sort.SliceStable(me.places, func(i, j int) bool { return me.places[i].Id < me.places[j].Id })
+	sort.SliceStable(me.postals, func(i, j int) bool {
+		pi, pj := &me.postals[i], &me.postals[j]
+		if c := bytes.Compare(pi.Country[:], pj.Country[:]); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(me.bytes(pi.Code), me.bytes(pj.Code)) < 0
+	})
+}
+
+//	One section to be written: `write` writes its `count` records of `recSize` bytes each.
+type payload struct {
+	kind           uint32
+	recSize, count int
+	write          func(io.Writer) error
+}
+
+func payloadOf[T any](kind uint32, recs []T) payload {
+	var zero T
+	return payload{kind: kind, recSize: binary.Size(zero), count: len(recs), write: func(w io.Writer) (err error) {
+		if len(recs) > 0 && littleEndian && int(unsafe.Sizeof(zero)) == binary.Size(zero) {
+			_, err = w.Write(unsafe.Slice((*byte)(unsafe.Pointer(&recs[0])), len(recs)*int(unsafe.Sizeof(zero))))
+		} else if len(recs) > 0 {
+			err = binary.Write(w, binary.LittleEndian, recs)
+		}
+		return
+	}}
+}
+
+//	Builds a tree over the records of `count` for which `lonLat` returns `ok`, with `Items` referring to record indices.
+func tree(count int, lonLat func(int) (lon, lat float64, ok bool)) *geonames_spatial.Tree {
+	var idx []int32
+	for i := 0; i < count; i++ {
+		if _, _, ok := lonLat(i); ok {
+			idx = append(idx, int32(i))
+		}
+	}
+	t := geonames_spatial.NewTree(len(idx), func(i int) (lon, lat float64) {
+		lon, lat, _ = lonLat(int(idx[i]))
+		return
+	})
+	for i, item := range t.Items {
+		t.Items[i] = idx[item]
+	}
+	return t
+}
+
+func (me *writer) write(w io.Writer) (err error) {
+	places := tree(len(me.places), func(i int) (float64, float64, bool) {
+		return me.places[i].Lon, me.places[i].Lat, me.places[i].Flags&flagLonLat != 0
+	})
+	payloads := []payload{
+		payloadOf(secStrings, me.strs),
+		payloadOf(secCountries, me.countries),
+		payloadOf(secTimezones, me.timezones),
+		payloadOf(secFeatures, me.features),
+		payloadOf(secAdmins, me.admins),
+		payloadOf(secPlaces, me.places),
+		payloadOf(secPlaceTreePoints, places.Points),
+		payloadOf(secPlaceTreeItems, places.Items),
+		payloadOf(secPlaceTreeAxes, places.Axes),
+	}
+	if !me.opts.SkipPostals {
+		postals := tree(len(me.postals), func(i int) (float64, float64, bool) {
+			return me.postals[i].Lon, me.postals[i].Lat, me.postals[i].Flags&flagLonLat != 0
+		})
+		payloads = append(payloads,
+			payloadOf(secPostals, me.postals),
+			payloadOf(secPostalTreePoints, postals.Points),
+			payloadOf(secPostalTreeItems, postals.Items),
+			payloadOf(secPostalTreeAxes, postals.Axes))
+	}
+
+	hdr := header{Major: FormatMajor, Minor: FormatMinor, NumSections: uint32(len(payloads)), Created: time.Now().Unix()}
+	copy(hdr.Magic[:], Magic)
+	sections, offset := make([]section, len(payloads)), align(binary.Size(hdr)+len(payloads)*binary.Size(section{}))
+	for i, p := range payloads {
+		sections[i] = section{Kind: p.kind, RecSize: uint32(p.recSize), Count: uint64(p.count), Offset: uint64(offset), Length: uint64(p.recSize * p.count)}
+		offset = align(offset + p.recSize*p.count)
+	}
+	if err = binary.Write(w, binary.LittleEndian, &hdr); err == nil {
+		if err = binary.Write(w, binary.LittleEndian, sections); err == nil {
+			written := binary.Size(hdr) + len(sections)*binary.Size(section{})
+			for i, p := range payloads {
+				if _, err = w.Write(make([]byte, int(sections[i].Offset)-written)); err != nil {
+					break
+				} else if err = p.write(w); err != nil {
+					break
+				}
+				written = int(sections[i].Offset + sections[i].Length)
+			}
+		}
+	}
+	return
+}
+
+//	Rounds `n` up to a multiple of 8.
+func align(n int) int {
+	return (n + 7) &^ 7
+}
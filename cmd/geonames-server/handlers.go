@@ -0,0 +1,272 @@
+package main
+
+import (
+	"time"
+
+	"github.com/go-geo/geonames/autocomplete"
+	"github.com/go-geo/geonames/import-dumps"
+	"github.com/go-geo/geonames/parse-dumps"
+	"github.com/go-geo/geonames/postal-codes"
+	"github.com/go-geo/geonames/reverse-geo"
+	"github.com/go-geo/geonames/search-names"
+)
+
+type placeJSON struct {
+	Id          int64    `json:"id"`
+	Name        string   `json:"name"`
+	NameAscii   string   `json:"nameAscii,omitempty"`
+	Lon         float64  `json:"lon"`
+	Lat         float64  `json:"lat"`
+	Feature     string   `json:"feature"`
+	Country     string   `json:"country"`
+	CountryName string   `json:"countryName,omitempty"`
+	Admin       []string `json:"admin,omitempty"`
+	Population  int64    `json:"population,omitempty"`
+	Elevation   int64    `json:"elevation,omitempty"`
+	Timezone    string   `json:"timezone,omitempty"`
+	DistanceKm  *float64 `json:"distanceKm,omitempty"`
+	Score       *float64 `json:"score,omitempty"`
+	MatchedName string   `json:"matchedName,omitempty"`
+}
+
+type completionJSON struct {
+	Kind       string  `json:"kind"`
+	Id         int64   `json:"id"`
+	Name       string  `json:"name"`
+	Match      string  `json:"match"`
+	Country    string  `json:"country"`
+	Feature    string  `json:"feature,omitempty"`
+	Population int64   `json:"population,omitempty"`
+	Lon        float64 `json:"lon"`
+	Lat        float64 `json:"lat"`
+}
+
+type postalJSON struct {
+	Country     string            `json:"country"`
+	CountryName string            `json:"countryName,omitempty"`
+	Code        string            `json:"code"`
+	PlaceName   string            `json:"placeName"`
+	Lon         float64           `json:"lon"`
+	Lat         float64           `json:"lat"`
+	Admins      map[string]string `json:"admins,omitempty"`
+	Accuracy    int64             `json:"accuracy,omitempty"`
+	DistanceKm  *float64          `json:"distanceKm,omitempty"`
+}
+
+type countryJSON struct {
+	Iso2         string   `json:"iso2"`
+	Iso3         string   `json:"iso3"`
+	IsoNumeric   string   `json:"isoNumeric"`
+	Fips         string   `json:"fips,omitempty"`
+	Name         string   `json:"name"`
+	Capital      string   `json:"capital,omitempty"`
+	AreaSqKm     int64    `json:"areaSqKm,omitempty"`
+	Population   int64    `json:"population,omitempty"`
+	Continent    string   `json:"continent"`
+	Tld          string   `json:"tld,omitempty"`
+	CurrencyCode string   `json:"currencyCode,omitempty"`
+	CurrencyName string   `json:"currencyName,omitempty"`
+	CallingCode  string   `json:"callingCode,omitempty"`
+	PostalFormat string   `json:"postalFormat,omitempty"`
+	Languages    []string `json:"languages,omitempty"`
+	Neighbors    []string `json:"neighbors,omitempty"`
+	GeonameId    int64    `json:"geonameId,omitempty"`
+}
+
+func (ix *indexes) place(p *geonames_import.Place, distKm, score *float64, matched string) *placeJSON {
+	j := &placeJSON{Id: p.Id, Name: p.Name, NameAscii: p.NameAscii, Lon: p.LonLat[0], Lat: p.LonLat[1], Feature: p.Feature.Class + "." + p.Feature.Code,
+		Country: p.Country.Code, Admin: ix.admins.Place(&p.PlaceRec).Names(false), Population: p.Population, Elevation: p.Elevation,
+		Timezone: p.TimezoneName, DistanceKm: distKm, Score: score, MatchedName: matched}
+	for i, j2 := 0, len(j.Admin)-1; i < j2; i, j2 = i+1, j2-1 {
+		j.Admin[i], j.Admin[j2] = j.Admin[j2], j.Admin[i] // most-general first
+	}
+	if c := ix.store.CountryByCode(p.Country.Code); c != nil {
+		j.CountryName = c.Name
+	}
+	return j
+}
+
+func (ix *indexes) postal(r *geonames_postal.Result) *postalJSON {
+	p := r.Postal
+	j := &postalJSON{Country: p.CountryCode, Code: p.PostalCode, PlaceName: p.PlaceName, Lon: p.LonLat[0], Lat: p.LonLat[1], Admins: p.Admins, Accuracy: p.Accuracy}
+	if r.Country != nil {
+		j.CountryName = r.Country.Name
+	}
+	if r.DistanceKm > 0 {
+		j.DistanceKm = &r.DistanceKm
+	}
+	return j
+}
+
+func country(ix *indexes, q *query) (interface{}, error) {
+	code := q.str("code", true)
+	if q.err != nil {
+		return nil, q.err
+	}
+	if c := ix.countries.Lookup(code); c != nil {
+		return countryOf(c), nil
+	}
+	return nil, errNotFound
+}
+
+func countryOf(c *geonames_parse.CountryRec) *countryJSON {
+	return &countryJSON{Iso2: c.Code.Iso2, Iso3: c.Code.Iso3, IsoNumeric: c.Code.IsoNum, Fips: c.Code.Fips, Name: c.Name, Capital: c.Capital,
+		AreaSqKm: c.AreaSqKm, Population: c.Population, Continent: c.Continent, Tld: c.Tld, CurrencyCode: c.Currency.Code, CurrencyName: c.Currency.Name,
+		CallingCode: c.CallingCode, PostalFormat: c.PostalCode.Format, Languages: c.Languages, Neighbors: c.Neighbors, GeonameId: c.Id}
+}
+
+func countries(ix *indexes, q *query, _ int) (items []interface{}, err error) {
+	all, continent := ix.countries.Countries, q.str("continent", false)
+	if len(continent) > 0 {
+		all = ix.countries.Continent(continent)
+	}
+	for _, c := range all {
+		items = append(items, countryOf(c))
+	}
+	return
+}
+
+func countryNeighbors(ix *indexes, q *query, _ int) (items []interface{}, err error) {
+	code, hops := q.str("code", true), q.int("hops", 1)
+	if q.err != nil {
+		return nil, q.err
+	}
+	c := ix.countries.Lookup(code)
+	if c == nil {
+		return nil, errNotFound
+	}
+	for _, n := range ix.countries.Within(c.Code.Iso2, hops) {
+		items = append(items, countryOf(n))
+	}
+	return
+}
+
+func reverse(ix *indexes, q *query, max int) (items []interface{}, err error) {
+	lon, lat := q.lonLat()
+	filter := &geonames_reverse.Filter{FeatureClasses: q.str("classes", false), FeatureCodes: q.list("codes"), Countries: q.list("countries"),
+		MinPopulation: int64(q.int("minPopulation", 0)), MaxDistanceKm: q.float("maxKm", false)}
+	if q.err != nil {
+		return nil, q.err
+	}
+	for _, r := range ix.reverse.Nearest(lon, lat, max, filter) {
+		dist := r.DistanceKm
+		items = append(items, ix.place(r.Place, &dist, nil, ""))
+	}
+	return
+}
+
+func search(ix *indexes, q *query, max int) (items []interface{}, err error) {
+	text, fuzzy := q.str("q", true), q.bool("fuzzy")
+	if q.err != nil {
+		return nil, q.err
+	} else if fuzzy && !ix.fuzzy {
+		return nil, badRequest("fuzzy search is not enabled on this server")
+	}
+	var candidates []geonames_search.Candidate
+	if fuzzy {
+		candidates = ix.search.SearchFuzzy(text, max, nil)
+	} else {
+		candidates = ix.search.Search(text, max)
+	}
+	for _, c := range candidates {
+		score := c.Score
+		items = append(items, ix.place(c.Place, nil, &score, c.MatchedName))
+	}
+	return
+}
+
+func autocomplete(ix *indexes, q *query, max int) (items []interface{}, err error) {
+	prefix := q.str("q", true)
+	filter := &geonames_autocomplete.Filter{Countries: q.list("countries"), FeatureClasses: q.str("classes", false), FeatureCodes: q.list("codes")}
+	if q.err != nil {
+		return nil, q.err
+	}
+	for _, e := range ix.autocomplete.Complete(prefix, max, filter) {
+		j := &completionJSON{Kind: "place", Id: e.Id, Name: e.Name, Match: e.Match, Country: e.Country, Feature: e.Feature, Population: e.Population}
+		if e.Kind == geonames_autocomplete.KindPostal {
+			j.Kind = "postal"
+		}
+		if len(e.LonLat) == 2 {
+			j.Lon, j.Lat = e.LonLat[0], e.LonLat[1]
+		}
+		items = append(items, j)
+	}
+	return
+}
+
+func postalItems(ix *indexes, results []geonames_postal.Result) (items []interface{}, err error) {
+	for i := range results {
+		items = append(items, ix.postal(&results[i]))
+	}
+	return
+}
+
+//	Returns the first malformed parameter, or `errNoPostals` if postal codes were not loaded.
+func postalCheck(ix *indexes, q *query) error {
+	if q.err != nil {
+		return q.err
+	} else if ix.postals == nil {
+		return errNoPostals
+	}
+	return nil
+}
+
+func postal(ix *indexes, q *query, _ int) (items []interface{}, err error) {
+	country, code := q.str("country", true), q.str("code", true)
+	if err = postalCheck(ix, q); err != nil {
+		return
+	}
+	return postalItems(ix, ix.postals.Lookup(country, code))
+}
+
+func postalPrefix(ix *indexes, q *query, max int) (items []interface{}, err error) {
+	country, prefix := q.str("country", true), q.str("prefix", false)
+	if err = postalCheck(ix, q); err != nil {
+		return
+	}
+	return postalItems(ix, ix.postals.Prefix(country, prefix, max))
+}
+
+func postalNearest(ix *indexes, q *query, max int) (items []interface{}, err error) {
+	lon, lat := q.lonLat()
+	filter := &geonames_postal.Filter{Countries: q.list("countries"), MaxDistanceKm: q.float("maxKm", false)}
+	if err = postalCheck(ix, q); err != nil {
+		return
+	}
+	return postalItems(ix, ix.postals.Nearest(lon, lat, max, filter))
+}
+
+func postalValidate(ix *indexes, q *query) (interface{}, error) {
+	country, code := q.str("country", true), q.str("code", true)
+	if q.err != nil {
+		return nil, q.err
+	}
+	v := ix.postalCodes.Validate(country, code)
+	return map[string]interface{}{"normalized": v.Normalized, "known": v.Known, "valid": v.Valid, "exists": v.Exists}, nil
+}
+
+func timezone(ix *indexes, q *query) (interface{}, error) {
+	lon, lat := q.lonLat()
+	at := time.Now()
+	if s := q.str("at", false); len(s) > 0 {
+		var err error
+		if at, err = time.Parse(time.RFC3339, s); err != nil {
+			q.fail("at", "an RFC 3339 timestamp")
+		}
+	}
+	if q.err != nil {
+		return nil, q.err
+	}
+	r, ok := ix.timezones.At(lon, lat, at)
+	if !ok {
+		return nil, errNotFound
+	}
+	j := map[string]interface{}{"name": r.Name, "offsetSeconds": int(r.Offset.Seconds()), "abbrev": r.Abbrev, "dst": r.IsDST, "distanceKm": r.DistanceKm}
+	if r.Place != nil {
+		j["placeId"], j["placeName"] = r.Place.Id, r.Place.Name
+	}
+	if r.Country != nil {
+		j["country"] = r.Country.Code.Iso2
+	}
+	return j, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/go-geo/geonames/admin-divisions"
+	"github.com/go-geo/geonames/autocomplete"
+	"github.com/go-geo/geonames/countries"
+	"github.com/go-geo/geonames/import-dumps"
+	"github.com/go-geo/geonames/mem-store"
+	"github.com/go-geo/geonames/parse-dumps"
+	"github.com/go-geo/geonames/postal-codes"
+	"github.com/go-geo/geonames/reverse-geo"
+	"github.com/go-geo/geonames/search-names"
+	"github.com/go-geo/geonames/time-zones"
+)
+
+type loadOptions struct {
+	DirPath        string
+	AlternateNames bool
+	Fuzzy          bool
+	Postals        bool
+}
+
+//	All indexes served, built once by `load` and read-only thereafter.
+type indexes struct {
+	store        *geonames_mem.Store
+	admins       *geonames_admin.Resolver
+	autocomplete *geonames_autocomplete.Index
+	countries    *geonames_countries.Table
+	postals      *geonames_postal.Index
+	postalCodes  *geonames_postal.Validator
+	reverse      *geonames_reverse.Index
+	search       *geonames_search.Index
+	timezones    *geonames_tz.Resolver
+	fuzzy        bool
+	loaded       time.Time
+	took         time.Duration
+}
+
+func load(opts *loadOptions) (me *indexes, err error) {
+	var skip []string
+	if !opts.Postals {
+		skip = append(skip, geonames_import.Postals)
+	}
+	start, geo := time.Now(), geonames_parse.NewIterator(opts.DirPath)
+	me = &indexes{fuzzy: opts.Fuzzy}
+	if me.store, err = geonames_mem.Load(geo, skip...); err != nil {
+		return nil, err
+	}
+	log.Print("Building indexes..")
+	recs := make([]geonames_parse.CountryRec, len(me.store.Countries))
+	for i, c := range me.store.Countries {
+		recs[i] = c.CountryRec
+	}
+	me.countries, me.admins = geonames_countries.New(recs), geonames_admin.NewResolver(me.store)
+	me.reverse = geonames_reverse.NewIndex(me.store, nil)
+	me.timezones = geonames_tz.NewResolver(me.reverse)
+	if opts.Postals {
+		me.postals = geonames_postal.NewIndex(me.store, nil) // else `nil`, so that postal lookups report they are unavailable
+	}
+	me.postalCodes = geonames_postal.NewValidator(me.store, me.postals)
+	me.autocomplete = geonames_autocomplete.NewIndex(me.store, &geonames_autocomplete.Options{Postals: opts.Postals})
+	me.search = geonames_search.NewIndex(me.store, nil)
+	if opts.AlternateNames {
+		if err = me.search.AddAlternateNames(geo); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Fuzzy {
+		me.search.BuildFuzzy()
+	}
+	me.loaded = time.Now()
+	me.took = me.loaded.Sub(start)
+	log.Printf("Ready after %s.", me.took)
+	return
+}
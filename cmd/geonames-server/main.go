@@ -0,0 +1,108 @@
+//	Serves reverse geocoding, name search, autocompletion, postal-code, country and time-zone queries over HTTP/JSON
+//	from in-memory indexes built from local `download.geonames.org/export/dump` files (as fetched by the `fetch-dumps` package).
+//
+//	Usage:
+//
+//		geonames-server -dumps <dir> [-addr :8080] [-grpc-addr :9090] [-alternate-names] [-fuzzy] [-postals=false]
+//
+//	With `-grpc-addr`, the `grpc-api` service is additionally served there once all indexes are loaded.
+//
+//	All endpoints answer `GET` requests. Those returning lists accept `offset` and `limit` (default `10`, at most `MaxLimit`,
+//	with `offset + limit` at most `MaxOffset`) and wrap their results as `{"offset": .., "limit": .., "more": .., "items": [..]}`. All endpoints accept `fields`,
+//	a comma-separated list of the JSON properties to return per result (all if omitted).
+//
+//		/healthz                    liveness: always `200` while the process serves requests
+//		/readyz                     readiness: `200` once all indexes are loaded, else `503`
+//		/v1/reverse                 lon, lat, classes, codes, countries, minPopulation, maxKm
+//		/v1/search                  q (eg. `Springfield, IL, US`), fuzzy
+//		/v1/autocomplete            q, classes, codes, countries
+//		/v1/postal                  country, code
+//		/v1/postal/prefix           country, prefix
+//		/v1/postal/nearest          lon, lat, countries, maxKm
+//		/v1/postal/validate         country, code
+//		/v1/countries               continent
+//		/v1/country                 code (ISO-3166 alpha-2, alpha-3, numeric, FIPS or name)
+//		/v1/country/neighbors       code, hops
+//		/v1/timezone                lon, lat, at (RFC 3339, default now)
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/go-geo/geonames/grpc-api"
+)
+
+var (
+	//	Upper bound of the `limit` query parameter
+	MaxLimit = 100
+
+	//	Default of the `limit` query parameter
+	DefaultLimit = 10
+
+	//	Upper bound of `offset + limit`, as every page fetches (and discards) all results before it
+	MaxOffset = 1000
+)
+
+func main() {
+	var (
+		addr, grpcAddr string
+		opts           loadOptions
+		grpcSrv        *grpc.Server
+	)
+	flag.StringVar(&addr, "addr", ":8080", "TCP address to listen on")
+	flag.StringVar(&grpcAddr, "grpc-addr", "", "TCP address to serve gRPC on (none if empty)")
+	flag.StringVar(&opts.DirPath, "dumps", ".", "directory containing the GeoNames dump files")
+	flag.BoolVar(&opts.AlternateNames, "alternate-names", false, "also search alternateNames.txt")
+	flag.BoolVar(&opts.Fuzzy, "fuzzy", false, "build the fuzzy-search index")
+	flag.BoolVar(&opts.Postals, "postals", true, "load postal codes")
+	flag.Parse()
+
+	srv := newServer()
+	if len(grpcAddr) > 0 {
+		grpcSrv = grpc.NewServer()
+	}
+	go func() {
+		if err := srv.load(&opts); err != nil {
+			log.Printf("Loading %s failed: %v", opts.DirPath, err)
+		} else if grpcSrv != nil {
+			ix := srv.ix.Load()
+			geonames_grpc.RegisterGeonamesServer(grpcSrv, &geonames_grpc.Server{Admins: ix.admins, Countries: ix.countries, Postals: ix.postals,
+				Reverse: ix.reverse, Names: ix.search, Timezones: ix.timezones, Fuzzy: ix.fuzzy})
+			lis, err := net.Listen("tcp", grpcAddr)
+			if err == nil {
+				log.Printf("Serving gRPC on %s..", grpcAddr)
+				err = grpcSrv.Serve(lis)
+			}
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	httpSrv := &http.Server{Addr: addr, Handler: srv.mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if grpcSrv != nil {
+			grpcSrv.GracefulStop()
+		}
+		httpSrv.Shutdown(shutdownCtx)
+	}()
+	log.Printf("Listening on %s..", addr)
+	if err := httpSrv.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+var (
+	errNotFound  = errors.New("not found")
+	errNoPostals = errors.New("postal codes are not loaded on this server")
+)
+
+//	A client error, answered with status `400`.
+type badRequest string
+
+func (me badRequest) Error() string {
+	return string(me)
+}
+
+type server struct {
+	mux     *http.ServeMux
+	ix      atomic.Pointer[indexes]
+	loadErr atomic.Pointer[string]
+}
+
+func newServer() (me *server) {
+	me = &server{mux: http.NewServeMux()}
+	me.mux.HandleFunc("/healthz", me.healthz)
+	me.mux.HandleFunc("/readyz", me.readyz)
+	me.list("/v1/reverse", reverse)
+	me.list("/v1/search", search)
+	me.list("/v1/autocomplete", autocomplete)
+	me.list("/v1/postal", postal)
+	me.list("/v1/postal/prefix", postalPrefix)
+	me.list("/v1/postal/nearest", postalNearest)
+	me.object("/v1/postal/validate", postalValidate)
+	me.list("/v1/countries", countries)
+	me.object("/v1/country", country)
+	me.list("/v1/country/neighbors", countryNeighbors)
+	me.object("/v1/timezone", timezone)
+	return
+}
+
+func (me *server) load(opts *loadOptions) error {
+	ix, err := load(opts)
+	if err != nil {
+		msg := err.Error()
+		me.loadErr.Store(&msg)
+		return err
+	}
+	me.ix.Store(ix)
+	return nil
+}
+
+func (me *server) healthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok"})
+}
+
+func (me *server) readyz(w http.ResponseWriter, r *http.Request) {
+	if ix := me.ix.Load(); ix != nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ready", "loaded": ix.loaded, "loadSeconds": ix.took.Seconds(),
+			"places": len(ix.store.Places), "postals": len(ix.store.Postals), "countries": len(ix.store.Countries)})
+	} else if msg := me.loadErr.Load(); msg != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"status": "failed", "error": *msg})
+	} else {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"status": "loading"})
+	}
+}
+
+//	Registers a handler for `pattern` returning a paginated list: `fetch` is to return at least `max` results if there are that many.
+func (me *server) list(pattern string, fetch func(ix *indexes, q *query, max int) ([]interface{}, error)) {
+	me.handle(pattern, func(ix *indexes, q *query) (interface{}, error) {
+		offset, limit := q.int("offset", 0), q.int("limit", DefaultLimit)
+		if q.err != nil {
+			return nil, q.err
+		} else if offset < 0 || limit < 1 || limit > MaxLimit {
+			return nil, badRequest(fmt.Sprintf("offset must be >= 0 and limit within 1..%d", MaxLimit))
+		} else if offset > MaxOffset-limit {
+			return nil, badRequest(fmt.Sprintf("offset + limit must not exceed %d", MaxOffset))
+		}
+		items, err := fetch(ix, q, offset+limit+1)
+		if err != nil {
+			return nil, err
+		}
+		page := &page{Offset: offset, Limit: limit, More: len(items) > offset+limit, Items: []interface{}{}}
+		if offset < len(items) {
+			if page.Items = items[offset:]; len(page.Items) > limit {
+				page.Items = page.Items[:limit]
+			}
+		}
+		for i := range page.Items {
+			page.Items[i] = q.selectFields(page.Items[i])
+		}
+		return page, nil
+	})
+}
+
+//	Registers a handler for `pattern` returning a single result.
+func (me *server) object(pattern string, fetch func(ix *indexes, q *query) (interface{}, error)) {
+	me.handle(pattern, func(ix *indexes, q *query) (obj interface{}, err error) {
+		if obj, err = fetch(ix, q); err == nil {
+			obj = q.selectFields(obj)
+		}
+		return
+	})
+}
+
+func (me *server) handle(pattern string, serve func(ix *indexes, q *query) (interface{}, error)) {
+	me.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		ix := me.ix.Load()
+		if ix == nil {
+			writeError(w, http.StatusServiceUnavailable, "indexes not loaded yet")
+			return
+		}
+		q := &query{Values: r.URL.Query()}
+		result, err := serve(ix, q)
+		if err == nil {
+			err = q.err
+		}
+		var bad badRequest
+		switch {
+		case errors.As(err, &bad):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case err == errNotFound:
+			writeError(w, http.StatusNotFound, err.Error())
+		case err == errNoPostals:
+			writeError(w, http.StatusNotImplemented, err.Error())
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		default:
+			writeJSON(w, http.StatusOK, result)
+		}
+	})
+}
+
+//	The envelope of all list results.
+type page struct {
+	Offset int           `json:"offset"`
+	Limit  int           `json:"limit"`
+	More   bool          `json:"more"`
+	Items  []interface{} `json:"items"`
+}
+
+//	Query-string accessors recording the first malformed parameter in `err`.
+type query struct {
+	url.Values
+	err error
+}
+
+func (me *query) fail(name, expected string) {
+	if me.err == nil {
+		me.err = badRequest(fmt.Sprintf("%s: expected %s, got %#v", name, expected, me.Get(name)))
+	}
+}
+
+func (me *query) str(name string, required bool) (s string) {
+	if s = strings.TrimSpace(me.Get(name)); required && len(s) == 0 {
+		me.fail(name, "a value")
+	}
+	return
+}
+
+func (me *query) list(name string) (vals []string) {
+	for _, v := range strings.Split(me.Get(name), ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			vals = append(vals, v)
+		}
+	}
+	return
+}
+
+func (me *query) int(name string, def int) int {
+	if s := me.str(name, false); len(s) > 0 {
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			me.fail(name, "an integer")
+		}
+		return i
+	}
+	return def
+}
+
+func (me *query) float(name string, required bool) float64 {
+	if s := me.str(name, required); len(s) > 0 {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			me.fail(name, "a finite number")
+		}
+		return f
+	}
+	return 0
+}
+
+func (me *query) bool(name string) bool {
+	if s := me.str(name, false); len(s) > 0 {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			me.fail(name, "a boolean")
+		}
+		return b
+	}
+	return false
+}
+
+func (me *query) lonLat() (lon, lat float64) {
+	if lon, lat = me.float("lon", true), me.float("lat", true); me.err == nil && (lon < -180 || lon > 180) {
+		me.fail("lon", "a longitude within -180..180")
+	} else if me.err == nil && (lat < -90 || lat > 90) {
+		me.fail("lat", "a latitude within -90..90")
+	}
+	return
+}
+
+//	Returns `obj` reduced to the JSON properties named in the `fields` parameter, or `obj` itself if there is none.
+func (me *query) selectFields(obj interface{}) interface{} {
+	fields := me.list("fields")
+	if len(fields) == 0 || me.err != nil {
+		return obj
+	}
+	raw, err := json.Marshal(obj)
+	var all map[string]json.RawMessage
+	if err == nil {
+		err = json.Unmarshal(raw, &all)
+	}
+	if err != nil {
+		me.err = err
+		return obj
+	}
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, f := range fields {
+		if v, ok := all[f]; ok {
+			selected[f] = v
+		}
+	}
+	return selected
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]interface{}{"error": msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/go-geo/geonames/data-quality"
+	"github.com/go-geo/geonames/dump-diff"
+	"github.com/go-geo/geonames/fetch-dumps"
+	"github.com/go-geo/geonames/import-dumps"
+	"github.com/go-geo/geonames/make-mongodb"
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+func fetch(args []string) (err error) {
+	fs := flags("fetch")
+	dir := fs.String("dir", ".", "directory to download into")
+	altNames := fs.Bool("alternate-names", false, "also download alternateNames.txt (large) and iso-languagecodes.txt")
+	if err = parse(fs, args); err == nil {
+		geonames_fetch.FetchAlternateNames = *altNames
+		if err = os.MkdirAll(*dir, 0755); err == nil {
+			if errs := geonames_fetch.FetchAllFiles(*dir); len(errs) > 0 {
+				for _, e := range errs {
+					fmt.Fprintln(os.Stderr, e)
+				}
+				err = fmt.Errorf("%d files failed", len(errs))
+			}
+		}
+	}
+	return
+}
+
+func inspect(args []string) (err error) {
+	fs := flags("inspect")
+	dir := fs.String("dir", ".", "directory containing the dump files")
+	checksums := fs.Bool("checksums", false, "also compute SHA-256 checksums")
+	count := fs.Bool("count", true, "also parse and count all records")
+	if err = parse(fs, args); err != nil {
+		return
+	} else if err = checkDir("dir", *dir); err != nil {
+		return
+	}
+	var files []geonames_fetch.LocalFile
+	if files, err = geonames_fetch.LocalFiles(*dir, *checksums); err != nil {
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tSIZE\tMODIFIED\tPUBLISHED\tSHA-256")
+	for _, f := range files {
+		published := "-"
+		if !f.LastModified.IsZero() {
+			published = f.LastModified.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", f.Name, f.Size, f.ModTime.Format(time.RFC3339), published, f.Sha256)
+	}
+	if err = w.Flush(); err == nil && *count {
+		imp := geonames_import.NewImporter(discard{})
+		imp.Log = false
+		if err = imp.Run(geonames_parse.NewIterator(*dir)); err == nil {
+			fmt.Println()
+			fmt.Fprintln(w, "COLLECTION\tREAD\tWRITTEN\tSKIPPED\tINVALID\tORPHANS")
+			for _, name := range []string{geonames_import.Timezones, geonames_import.Features, geonames_import.Countries, geonames_import.Admins, geonames_import.Postals, geonames_import.Places} {
+				s := imp.Stats[name]
+				fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\n", name, s.Read, s.Written, s.Skipped, s.Invalid, s.Orphans)
+			}
+			err = w.Flush()
+		}
+	}
+	return
+}
+
+func quality(args []string) (err error) {
+	fs := flags("quality")
+	dir := fs.String("dir", ".", "directory containing the dump files")
+	samples := fs.Int("samples", geonames_quality.MaxSamples, "sample records listed per check")
+	integrity := fs.Bool("integrity", false, "only report orphans (records referring to codes not defined in the other files), and fail if any")
+	asJSON := fs.Bool("json", false, "print a JSON report")
+	if err = parse(fs, args); err != nil {
+		return
+	} else if err = checkDir("dir", *dir); err != nil {
+		return
+	}
+	geonames_quality.MaxSamples = *samples
+	var report *geonames_quality.Report
+	if report, err = geonames_quality.Check(geonames_parse.NewIterator(*dir)); err != nil {
+		return
+	}
+	if *integrity {
+		for _, c := range geonames_quality.Checks {
+			if !c.Reference {
+				delete(report.Issues, c.Name)
+			}
+		}
+	}
+	if *asJSON {
+		err = printJSON(report)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if orphans := report.Orphans(); err == nil && *integrity && orphans > 0 {
+		err = fmt.Errorf("%d orphan records", orphans)
+	}
+	return
+}
+
+//	A `geonames_import.Sink` that drops all records.
+type discard struct{}
+
+func (discard) BeginCollection(string, int) error { return nil }
+func (discard) EndCollection(string) error        { return nil }
+func (discard) Finish() error                     { return nil }
+func (discard) Write([]interface{}) error         { return nil }
+
+//	Registers the flags shared by `export` and `import-mongo`, and returns a func applying them.
+func importFlags(fs *flag.FlagSet) (dir, db *string, apply func() error) {
+	dir = fs.String("dir", ".", "directory containing the dump files")
+	db = fs.String("db", "geonames", "database name")
+	profile := fs.String("profile", "compact", "document schema: compact, verbose, or the path of a JSON schema file")
+	titleAllUpper := fs.Int("title-all-upper", 1, "title-case all-upper-case names longer than this (0 disables)")
+	quiet := fs.Bool("q", false, "do not log progress")
+	strict := fs.Bool("strict", false, "fail on records referring to unknown countries, admin divisions, features or time zones")
+	apply = func() (err error) {
+		if err = checkDir("dir", *dir); err != nil {
+			return
+		}
+		switch *profile {
+		case "compact":
+			geonames_makedb.UseSchema = geonames_makedb.SchemaCompact()
+		case "verbose":
+			geonames_makedb.UseSchema = geonames_makedb.SchemaVerbose()
+		default:
+			if geonames_makedb.UseSchema, err = geonames_makedb.LoadSchema(*profile); err != nil {
+				return usageError(fmt.Sprintf("-profile: %v", err))
+			}
+		}
+		geonames_makedb.SchemaProfile = *profile
+		geonames_makedb.TitleAllUpper, geonames_makedb.Log, geonames_makedb.Strict = *titleAllUpper, !*quiet, *strict
+		return geonames_makedb.UseSchema.Validate()
+	}
+	return
+}
+
+func importMongo(args []string) (err error) {
+	fs := flags("import-mongo")
+	dir, db, apply := importFlags(fs)
+	uri := fs.String("uri", "mongodb://localhost:27017", "MongoDB connection string")
+	noIndexes := fs.Bool("no-indexes", false, "do not create indexes")
+	if err = parse(fs, args); err != nil {
+		return
+	} else if err = apply(); err != nil {
+		return
+	}
+	geonames_makedb.CreateIndexes = !*noIndexes
+	var client *mongo.Client
+	if client, err = mongo.Connect(options.Client().ApplyURI(*uri)); err == nil {
+		defer client.Disconnect(context.Background())
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err = client.Ping(ctx, nil)
+		if cancel(); err == nil {
+			err = geonames_makedb.Insert(geonames_parse.NewIterator(*dir), client.Database(*db))
+		}
+	}
+	return
+}
+
+//	One added, removed or modified dump file, as reported by `diff`.
+type fileChange struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	SizeDelta int64  `json:"sizeDelta"`
+}
+
+func diff(args []string) (err error) {
+	fs := flags("diff")
+	oldDir := fs.String("old", "", "directory containing the older dump files")
+	newDir := fs.String("new", ".", "directory containing the newer dump files")
+	checksums := fs.Bool("checksums", true, "compare SHA-256 checksums (else sizes only)")
+	records := fs.Bool("records", true, "also compare places, postal codes and admin divisions record by record (holds all older records in memory, some 8 GB for complete dumps)")
+	postals := fs.Bool("postals", true, "with -records: also compare the postal-codes files")
+	ignore := fs.String("ignore", "", "with -records: comma-separated fields not to compare, eg. modification_date")
+	maxChanges := fs.Int("max-changes", 100, "with -records: maximum changes listed per kind of record (0 for all)")
+	asJSON := fs.Bool("json", false, "print a JSON report")
+	if err = parse(fs, args); err != nil {
+		return
+	} else if err = checkDir("old", *oldDir); err != nil {
+		return
+	} else if err = checkDir("new", *newDir); err != nil {
+		return
+	}
+	var (
+		oldFiles, newFiles []geonames_fetch.LocalFile
+		files              []fileChange
+		report             *geonames_diff.Report
+	)
+	if oldFiles, err = geonames_fetch.LocalFiles(*oldDir, *checksums); err != nil {
+		return
+	} else if newFiles, err = geonames_fetch.LocalFiles(*newDir, *checksums); err != nil {
+		return
+	}
+	olds := map[string]geonames_fetch.LocalFile{}
+	for _, f := range oldFiles {
+		olds[f.Name] = f
+	}
+	for _, f := range newFiles {
+		if o, ok := olds[f.Name]; !ok {
+			files = append(files, fileChange{Name: f.Name, Kind: geonames_diff.Added, SizeDelta: f.Size})
+		} else if o.Size != f.Size || o.Sha256 != f.Sha256 {
+			files = append(files, fileChange{Name: f.Name, Kind: geonames_diff.Modified, SizeDelta: f.Size - o.Size})
+		}
+		delete(olds, f.Name)
+	}
+	for _, f := range oldFiles {
+		if _, removed := olds[f.Name]; removed {
+			files = append(files, fileChange{Name: f.Name, Kind: geonames_diff.Removed, SizeDelta: -f.Size})
+		}
+	}
+	if *records {
+		opts := &geonames_diff.Options{MaxChanges: *maxChanges, SkipPostals: !*postals}
+		if len(*ignore) > 0 {
+			opts.Ignore = strings.Split(*ignore, ",")
+		}
+		if report, err = geonames_diff.Compare(geonames_parse.NewIterator(*oldDir), geonames_parse.NewIterator(*newDir), opts); err != nil {
+			return
+		}
+	}
+	if *asJSON {
+		return printJSON(map[string]interface{}{"files": files, "records": report})
+	}
+	marks := map[string]string{geonames_diff.Added: "+", geonames_diff.Removed: "-", geonames_diff.Modified: "~"}
+	for _, f := range files {
+		fmt.Printf("%s %s (%+d bytes)\n", marks[f.Kind], f.Name, f.SizeDelta)
+	}
+	if report != nil {
+		if len(files) > 0 {
+			fmt.Println()
+		}
+		err = report.WriteText(os.Stdout)
+	}
+	return
+}
+
+//	Parses a `lon,lat` flag value.
+func parseLonLat(flagName, s string) (lon, lat float64, err error) {
+	if parts := strings.Split(s, ","); len(parts) == 2 {
+		if lon, err = strconv.ParseFloat(strings.TrimSpace(parts[0]), 64); err == nil {
+			if lat, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64); err == nil && lon >= -180 && lon <= 180 && lat >= -90 && lat <= 90 {
+				return
+			}
+		}
+	}
+	return 0, 0, usageError(fmt.Sprintf("-%s: expected lon,lat in degrees, got %#v", flagName, s))
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+var errNotFound = errors.New("not found")
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-geo/geonames/binary-snapshot"
+	"github.com/go-geo/geonames/export-files"
+	"github.com/go-geo/geonames/make-mongodb"
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+//	Returns a `usageError` if any of the flags `names` was given, as they only apply to `-format <format>`.
+func onlyWith(fs *flag.FlagSet, format string, names ...string) (err error) {
+	fs.Visit(func(f *flag.Flag) {
+		for _, name := range names {
+			if err == nil && f.Name == name {
+				err = usageError(fmt.Sprintf("-%s: only applies to -format %s", name, format))
+			}
+		}
+	})
+	return
+}
+
+func export(args []string) (err error) {
+	fs := flags("export")
+	dir, db, apply := importFlags(fs)
+	format := fs.String("format", "mongo", "output format: mongo, parquet, geojson, ndjson, csv or snapshot")
+	out := fs.String("out", "", "output directory for -format mongo (default \"dump\", files are written into its -db subdirectory) and parquet, output file for -format snapshot, else output file (default stdout)")
+	jsonLines := fs.Bool("json", false, "with -format mongo: write extended-JSON lines instead of BSON")
+	partition := fs.Bool("partition", false, "with -format parquet: partition places, postals and admins by country")
+	altNames := fs.Bool("alternate-names", false, "with -format parquet: also export alternateNames.txt; with -format snapshot: also store the alternate names of places")
+	records := fs.String("records", "places", "with -format geojson or ndjson: places or postals; with -format csv also admins, countries, features, timezones, hierarchy, languages or alternate_names")
+	columns := fs.String("columns", "", "with -format csv: comma-separated columns to write, each optionally renamed as column=Header (default: all default columns)")
+	filter := filterFlags(fs)
+	if err = parse(fs, args); err != nil {
+		return
+	} else if *format != "mongo" {
+		if err = onlyWith(fs, "mongo", "db", "profile", "strict", "title-all-upper"); err != nil {
+			return
+		}
+	}
+	if err = apply(); err != nil {
+		return
+	}
+	geo := geonames_parse.NewIterator(*dir)
+	switch *format {
+	case "mongo":
+		if len(*out) == 0 {
+			*out = "dump"
+		}
+		return geonames_makedb.Dump(geo, *out, *db, *jsonLines)
+	case "snapshot":
+		if len(*out) == 0 {
+			return usageError("-out: required for -format snapshot")
+		}
+		if err = geonames_snapshot.Write(geo, *out, &geonames_snapshot.Options{AlternateNames: *altNames}); err == nil {
+			fmt.Fprintf(os.Stderr, "%s: snapshot format %d.%d written.\n", *out, geonames_snapshot.FormatMajor, geonames_snapshot.FormatMinor)
+		}
+		return
+	case "parquet", "geojson", "ndjson", "csv":
+	default:
+		return usageError(fmt.Sprintf("-format: unknown format %#v", *format))
+	}
+
+	var f *geonames_export.Filter
+	if f, err = filter(); err != nil {
+		return
+	}
+	if *format == "parquet" {
+		if len(*out) == 0 {
+			return usageError("-out: required for -format parquet")
+		}
+		var counts map[string]int
+		if counts, err = geonames_export.Parquet(geo, &geonames_export.ParquetOptions{DirPath: *out, PartitionByCountry: *partition,
+			AlternateNames: *altNames, Filter: f}); err == nil {
+			for _, table := range []string{"countries", "admins", "postals", "places", "alternate_names"} {
+				if count, ok := counts[table]; ok {
+					fmt.Fprintf(os.Stderr, "%s: %d rows written.\n", table, count)
+				}
+			}
+		}
+		return
+	}
+	if *format == "csv" {
+		var names *geonames_export.Names
+		if names, err = geonames_export.LoadNames(geo); err != nil {
+			return
+		}
+		return writeOut(*out, func(w io.Writer) (int, error) {
+			return geonames_export.Csv(geo, *records, w, geonames_export.ParseCsvColumns(*columns), names, f)
+		})
+	}
+	return writeOut(*out, func(w io.Writer) (count int, err error) {
+		switch lines := *format == "ndjson"; *records {
+		case "places":
+			count, err = geonames_export.PlacesGeoJSON(geo, w, lines, f)
+		case "postals":
+			count, err = geonames_export.PostalsGeoJSON(geo, w, lines, f)
+		default:
+			err = usageError(fmt.Sprintf("-records: expected places or postals, got %#v", *records))
+		}
+		return
+	})
+}
+
+//	Calls `write` with the file at `filePath` (or stdout if empty) and reports the record count to stderr.
+func writeOut(filePath string, write func(io.Writer) (int, error)) (err error) {
+	var (
+		file  *os.File
+		count int
+	)
+	if len(filePath) == 0 {
+		count, err = write(os.Stdout)
+	} else if file, err = os.Create(filePath); err == nil {
+		if count, err = write(file); err == nil {
+			err = file.Close()
+		} else {
+			file.Close()
+		}
+	}
+	if err == nil {
+		fmt.Fprintf(os.Stderr, "%d records written.\n", count)
+	}
+	return
+}
+
+//	Registers the record-filter flags shared by exports, and returns a func parsing them.
+func filterFlags(fs *flag.FlagSet) func() (*geonames_export.Filter, error) {
+	countries := fs.String("countries", "", "comma-separated ISO-3166 alpha-2 codes of acceptable countries")
+	classes := fs.String("classes", "", "acceptable feature classes, eg. P or PA")
+	codes := fs.String("codes", "", "comma-separated acceptable feature codes, eg. PPLC,PPLA")
+	minPopulation := fs.Int64("min-population", 0, "minimum population")
+	bbox := fs.String("bbox", "", "bounding box as minLon,minLat,maxLon,maxLat")
+	return func() (f *geonames_export.Filter, err error) {
+		f = &geonames_export.Filter{FeatureClasses: strings.ToUpper(*classes), MinPopulation: *minPopulation}
+		if len(*countries) > 0 {
+			f.Countries = strings.Split(strings.ToUpper(*countries), ",")
+		}
+		if len(*codes) > 0 {
+			f.FeatureCodes = strings.Split(strings.ToUpper(*codes), ",")
+		}
+		if len(*bbox) > 0 {
+			parts := strings.Split(*bbox, ",")
+			for _, p := range parts {
+				v, e := strconv.ParseFloat(strings.TrimSpace(p), 64)
+				if e != nil {
+					break
+				}
+				f.BBox = append(f.BBox, v)
+			}
+			if len(parts) != 4 || len(f.BBox) != 4 {
+				return nil, usageError(fmt.Sprintf("-bbox: expected minLon,minLat,maxLon,maxLat, got %#v", *bbox))
+			}
+		}
+		return
+	}
+}
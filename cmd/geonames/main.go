@@ -0,0 +1,101 @@
+//	Fetches, inspects, exports, imports, queries and compares `download.geonames.org/export/dump` files.
+//
+//	Usage:
+//
+//		geonames <command> [flags]
+//
+//	Commands:
+//
+//		fetch         download all dump files into -dir
+//		inspect       list the dump files in -dir and count their records
+//		export        write -dir as a `mongorestore`-compatible dump, Parquet, GeoJSON, NDJSON, CSV or binary snapshot into -out
+//		import-mongo  import -dir into the MongoDB database -db at -uri
+//		query         answer one query (-search, -near, -postal, -country or -tz) over -dir
+//		quality       report invalid, unresolved, orphaned and duplicate records in -dir
+//		diff          compare the dump files in -old and -new, and their records
+//
+//	Run `geonames <command> -h` for the flags of each command. All commands exit with status `1` on failure and `2` on invalid usage.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+//	A subcommand: `run` receives the arguments following the command name.
+type command struct {
+	summary string
+	run     func(args []string) error
+}
+
+var commands = map[string]*command{
+	"fetch":        {"download all dump files", fetch},
+	"inspect":      {"list dump files and count their records", inspect},
+	"export":       {"write a mongorestore dump, Parquet, GeoJSON, NDJSON, CSV or snapshot", export},
+	"import-mongo": {"import into a MongoDB database", importMongo},
+	"query":        {"answer one query over the dump files", query},
+	"quality":      {"report data quality issues in the dump files", quality},
+	"diff":         {"compare two sets of dump files", diff},
+}
+
+//	Returned by commands for invalid arguments (exit status `2`).
+type usageError string
+
+func (me usageError) Error() string {
+	return string(me)
+}
+
+func main() {
+	if len(os.Args) < 2 || commands[os.Args[1]] == nil {
+		usage()
+		os.Exit(2)
+	}
+	if err := commands[os.Args[1]].run(os.Args[2:]); err == flag.ErrHelp {
+		os.Exit(0)
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "geonames %s: %v\n", os.Args[1], err)
+		if _, ok := err.(usageError); ok {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(os.Stderr, "Usage: geonames <command> [flags]\n\nCommands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-14s%s\n", name, commands[name].summary)
+	}
+}
+
+//	Returns a new `flag.FlagSet` for command `name` that reports errors instead of exiting.
+func flags(name string) *flag.FlagSet {
+	return flag.NewFlagSet("geonames "+name, flag.ContinueOnError)
+}
+
+//	Parses `args` into `fs`, rejecting positional arguments. Errors other than `flag.ErrHelp` are `usageError`s.
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err == flag.ErrHelp {
+		return err
+	} else if err != nil {
+		return usageError(err.Error())
+	} else if fs.NArg() > 0 {
+		return usageError(fmt.Sprintf("unexpected arguments: %v", fs.Args()))
+	}
+	return nil
+}
+
+//	Returns a `usageError` unless `dirPath` is an existing directory.
+func checkDir(flagName, dirPath string) error {
+	if fi, err := os.Stat(dirPath); err != nil || !fi.IsDir() {
+		return usageError(fmt.Sprintf("-%s: %#v is not a directory", flagName, dirPath))
+	}
+	return nil
+}
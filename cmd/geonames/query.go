@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-geo/geonames/admin-divisions"
+	"github.com/go-geo/geonames/countries"
+	"github.com/go-geo/geonames/import-dumps"
+	"github.com/go-geo/geonames/mem-store"
+	"github.com/go-geo/geonames/parse-dumps"
+	"github.com/go-geo/geonames/postal-codes"
+	"github.com/go-geo/geonames/reverse-geo"
+	"github.com/go-geo/geonames/search-names"
+	"github.com/go-geo/geonames/time-zones"
+)
+
+//	A place as printed by `query`.
+type placeOut struct {
+	Id         int64     `json:"id"`
+	Name       string    `json:"name"`
+	Feature    string    `json:"feature"`
+	Country    string    `json:"country"`
+	Admin      []string  `json:"admin,omitempty"`
+	LonLat     []float64 `json:"lonLat"`
+	Population int64     `json:"population,omitempty"`
+	Timezone   string    `json:"timezone,omitempty"`
+	DistanceKm float64   `json:"distanceKm,omitempty"`
+	Score      float64   `json:"score,omitempty"`
+}
+
+//	A postal code as printed by `query`.
+type postalOut struct {
+	Country   string    `json:"country"`
+	Code      string    `json:"code"`
+	PlaceName string    `json:"placeName"`
+	Admin     []string  `json:"admin,omitempty"`
+	LonLat    []float64 `json:"lonLat,omitempty"`
+	Accuracy  int64     `json:"accuracy,omitempty"`
+}
+
+func query(args []string) (err error) {
+	fs := flags("query")
+	dir := fs.String("dir", ".", "directory containing the dump files")
+	search := fs.String("search", "", "find places by name, eg. \"Springfield, IL, US\"")
+	fuzzy := fs.Bool("fuzzy", false, "with -search: also match misspelled or similar-sounding names")
+	near := fs.String("near", "", "find the places nearest to lon,lat")
+	postal := fs.String("postal", "", "look up a postal code as CC:CODE (eg. DE:80331), or a prefix as CC:CODE*")
+	country := fs.String("country", "", "look up a country by code or name")
+	tz := fs.String("tz", "", "resolve the time zone at lon,lat")
+	limit := fs.Int("limit", 10, "maximum number of results")
+	countryCodes := fs.String("countries", "", "with -near: comma-separated ISO-3166 alpha-2 codes of acceptable countries")
+	classes := fs.String("classes", "", "with -near: acceptable feature classes, eg. P or PA")
+	minPopulation := fs.Int64("min-population", 0, "with -near: minimum population")
+	if err = parse(fs, args); err != nil {
+		return
+	} else if err = checkDir("dir", *dir); err != nil {
+		return
+	}
+	var given int
+	for _, q := range []string{*search, *near, *postal, *country, *tz} {
+		if len(q) > 0 {
+			given++
+		}
+	}
+	if given != 1 {
+		return usageError("exactly one of -search, -near, -postal, -country or -tz is required")
+	} else if *limit < 1 {
+		return usageError("-limit must be positive")
+	}
+
+	var (
+		store *geonames_mem.Store
+		skip  []string
+	)
+	if len(*postal) == 0 {
+		skip = append(skip, geonames_import.Postals)
+	}
+	geonames_mem.Log = false
+	if store, err = geonames_mem.Load(geonames_parse.NewIterator(*dir), skip...); err != nil {
+		return
+	}
+	admins := geonames_admin.NewResolver(store)
+	place := func(p *geonames_import.Place) *placeOut {
+		out := &placeOut{Id: p.Id, Name: p.Name, Feature: p.Feature.Class + "." + p.Feature.Code, Country: p.Country.Code, LonLat: p.LonLat,
+			Population: p.Population, Timezone: p.TimezoneName}
+		for _, d := range admins.Place(&p.PlaceRec) {
+			if d != nil {
+				out.Admin = append(out.Admin, d.Name)
+			}
+		}
+		return out
+	}
+
+	var results []interface{}
+	switch {
+	case len(*search) > 0:
+		ix := geonames_search.NewIndex(store, nil)
+		var candidates []geonames_search.Candidate
+		if *fuzzy {
+			ix.BuildFuzzy()
+			candidates = ix.SearchFuzzy(*search, *limit, nil)
+		} else {
+			candidates = ix.Search(*search, *limit)
+		}
+		for _, c := range candidates {
+			p := place(c.Place)
+			p.Score = c.Score
+			results = append(results, p)
+		}
+	case len(*near) > 0:
+		var lon, lat float64
+		if lon, lat, err = parseLonLat("near", *near); err != nil {
+			return
+		}
+		filter := &geonames_reverse.Filter{FeatureClasses: *classes, MinPopulation: *minPopulation}
+		if len(*countryCodes) > 0 {
+			filter.Countries = strings.Split(strings.ToUpper(*countryCodes), ",")
+		}
+		for _, r := range geonames_reverse.NewIndex(store, nil).Nearest(lon, lat, *limit, filter) {
+			p := place(r.Place)
+			p.DistanceKm = r.DistanceKm
+			results = append(results, p)
+		}
+	case len(*postal) > 0:
+		cc, code, ok := strings.Cut(*postal, ":")
+		if !ok || len(cc) == 0 || len(code) == 0 {
+			return usageError(fmt.Sprintf("-postal: expected CC:CODE, got %#v", *postal))
+		}
+		ix := geonames_postal.NewIndex(store, nil)
+		var found []geonames_postal.Result
+		if prefix := strings.TrimSuffix(code, "*"); prefix != code {
+			found = ix.Prefix(cc, prefix, *limit)
+		} else {
+			found = ix.Lookup(cc, code)
+		}
+		for _, r := range found {
+			out := &postalOut{Country: r.Postal.CountryCode, Code: r.Postal.PostalCode, PlaceName: r.Postal.PlaceName,
+				LonLat: r.Postal.LonLat, Accuracy: r.Postal.Accuracy}
+			for _, name := range []string{r.Postal.Admin.Name1, r.Postal.Admin.Name2, r.Postal.Admin.Name3} {
+				if len(name) > 0 {
+					out.Admin = append(out.Admin, name)
+				}
+			}
+			results = append(results, out)
+		}
+	case len(*country) > 0:
+		recs := make([]geonames_parse.CountryRec, len(store.Countries))
+		for i, c := range store.Countries {
+			recs[i] = c.CountryRec
+		}
+		if c := geonames_countries.New(recs).Lookup(*country); c != nil {
+			results = append(results, c)
+		}
+	case len(*tz) > 0:
+		var lon, lat float64
+		if lon, lat, err = parseLonLat("tz", *tz); err != nil {
+			return
+		}
+		if r, ok := geonames_tz.NewResolver(geonames_reverse.NewIndex(store, nil)).At(lon, lat, time.Now()); ok {
+			results = append(results, map[string]interface{}{"name": r.Name, "offset": r.Offset.String(), "abbrev": r.Abbrev, "dst": r.IsDST, "distanceKm": r.DistanceKm})
+		}
+	}
+	if len(results) == 0 {
+		return errNotFound
+	}
+	for _, r := range results {
+		if err = printJSON(r); err != nil {
+			break
+		}
+	}
+	return
+}
@@ -0,0 +1,242 @@
+//	Country information (codes, currency, calling code, languages, TLD, continent, neighbors) with lookups by
+//	ISO-3166 alpha-2, alpha-3 and numeric code, FIPS code and name, neighbor-graph traversal and continent grouping.
+//
+//	`Default` serves a table generated from `countryInfo.txt` into `table.go`,
+//	while `New` and `Load` build one from any records, such as a freshly downloaded dump.
+//
+//	To regenerate `table.go`, point `$GEONAMES_DUMPS` to a directory containing a current `countryInfo.txt` (as fetched by `fetch-dumps` package):
+//
+//		GEONAMES_DUMPS=/path/to/dumps go generate ./countries
+package geonames_countries
+
+//go:generate go run gen-table/main.go -dir=$GEONAMES_DUMPS -out=table.go
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+var (
+	//	English names of the continent codes used in `CountryRec.Continent`
+	ContinentNames = map[string]string{
+		"AF": "Africa",
+		"AN": "Antarctica",
+		"AS": "Asia",
+		"EU": "Europe",
+		"NA": "North America",
+		"OC": "Oceania",
+		"SA": "South America",
+	}
+
+	defaultTable *Table
+	defaultOnce  sync.Once
+)
+
+//	A read-only set of countries with lookups, safe for concurrent use.
+type Table struct {
+	//	All countries, sorted by ISO-3166 alpha-2 code
+	Countries []*geonames_parse.CountryRec
+
+	byIso2, byIso3, byNum, byFips, byName map[string]*geonames_parse.CountryRec
+}
+
+//	Returns the `Table` of all countries in the generated `table.go`, built on first use.
+func Default() *Table {
+	defaultOnce.Do(func() {
+		defaultTable = New(table)
+	})
+	return defaultTable
+}
+
+//	Loads all countries from `geo` into a new `Table`.
+func Load(geo *geonames_parse.Iterator) (me *Table, err error) {
+	var recs []geonames_parse.CountryRec
+	if err = geo.Countries(func(_ int, r *geonames_parse.CountryRec) { recs = append(recs, *r) }); err == nil {
+		me = New(recs)
+	}
+	return
+}
+
+//	Builds a new `Table` over copies of `recs`.
+func New(recs []geonames_parse.CountryRec) (me *Table) {
+	me = &Table{Countries: make([]*geonames_parse.CountryRec, len(recs)),
+		byIso2: map[string]*geonames_parse.CountryRec{}, byIso3: map[string]*geonames_parse.CountryRec{}, byNum: map[string]*geonames_parse.CountryRec{},
+		byFips: map[string]*geonames_parse.CountryRec{}, byName: map[string]*geonames_parse.CountryRec{},
+	}
+	for i := range recs {
+		c := recs[i]
+		me.Countries[i] = &c
+	}
+	sort.Slice(me.Countries, func(i, j int) bool { return me.Countries[i].Code.Iso2 < me.Countries[j].Code.Iso2 })
+	for _, c := range me.Countries {
+		me.byIso2[c.Code.Iso2], me.byName[strings.ToLower(c.Name)] = c, c
+		if len(c.Code.Iso3) > 0 {
+			me.byIso3[c.Code.Iso3] = c
+		}
+		if len(c.Code.IsoNum) > 0 {
+			me.byNum[strings.TrimLeft(c.Code.IsoNum, "0")] = c
+		}
+		if len(c.Code.Fips) > 0 {
+			me.byFips[c.Code.Fips] = c
+		}
+	}
+	return
+}
+
+//	Returns the country with the specified ISO-3166 alpha-2 code (eg. `DE`), or `nil`.
+func (me *Table) ByIso2(code string) *geonames_parse.CountryRec {
+	return me.byIso2[strings.ToUpper(code)]
+}
+
+//	Returns the country with the specified ISO-3166 alpha-3 code (eg. `DEU`), or `nil`.
+func (me *Table) ByIso3(code string) *geonames_parse.CountryRec {
+	return me.byIso3[strings.ToUpper(code)]
+}
+
+//	Returns the country with the specified ISO-3166 numeric code (eg. `276` or `040`), or `nil`.
+func (me *Table) ByNumeric(code string) *geonames_parse.CountryRec {
+	return me.byNum[strings.TrimLeft(code, "0")]
+}
+
+//	Returns the country with the specified FIPS 10-4 code (eg. `GM` for Germany), or `nil`.
+func (me *Table) ByFips(code string) *geonames_parse.CountryRec {
+	return me.byFips[strings.ToUpper(code)]
+}
+
+//	Returns the country with the specified English `name` (case-insensitive), or `nil`.
+func (me *Table) ByName(name string) *geonames_parse.CountryRec {
+	return me.byName[strings.ToLower(strings.TrimSpace(name))]
+}
+
+//	Returns the country identified by `s`, tried in this order as: ISO-3166 alpha-2, alpha-3 or numeric code, name, FIPS code. Returns `nil` if none matches.
+func (me *Table) Lookup(s string) (c *geonames_parse.CountryRec) {
+	if s = strings.TrimSpace(s); len(s) == 0 {
+		return
+	}
+	if strings.Trim(s, "0123456789") == "" {
+		return me.ByNumeric(s)
+	}
+	switch len(s) {
+	case 2:
+		c = me.ByIso2(s)
+	case 3:
+		c = me.ByIso3(s)
+	}
+	if c == nil {
+		if c = me.ByName(s); c == nil && len(s) == 2 {
+			c = me.ByFips(s)
+		}
+	}
+	return
+}
+
+//	Returns the known neighbors of the country with ISO-3166 alpha-2 code `iso2`.
+func (me *Table) Neighbors(iso2 string) (neighbors []*geonames_parse.CountryRec) {
+	if c := me.ByIso2(iso2); c != nil {
+		for _, n := range c.Neighbors {
+			if nc := me.byIso2[n]; nc != nil {
+				neighbors = append(neighbors, nc)
+			}
+		}
+	}
+	return
+}
+
+//	Returns all countries reachable from `iso2` by crossing at most `hops` land borders (excluding `iso2` itself),
+//	nearest first.
+func (me *Table) Within(iso2 string, hops int) (countries []*geonames_parse.CountryRec) {
+	me.walk(iso2, func(c *geonames_parse.CountryRec, _ *geonames_parse.CountryRec, hop int) bool {
+		if hop > hops {
+			return false
+		}
+		if hop > 0 {
+			countries = append(countries, c)
+		}
+		return true
+	})
+	return
+}
+
+//	Returns a shortest sequence of neighboring countries leading from `fromIso2` to `toIso2` (both included),
+//	or `nil` if there is none (such as for island nations).
+func (me *Table) Path(fromIso2, toIso2 string) (path []*geonames_parse.CountryRec) {
+	to, prev := me.ByIso2(toIso2), map[*geonames_parse.CountryRec]*geonames_parse.CountryRec{}
+	me.walk(fromIso2, func(c *geonames_parse.CountryRec, from *geonames_parse.CountryRec, _ int) bool {
+		prev[c] = from
+		return c != to
+	})
+	if _, ok := prev[to]; to != nil && ok {
+		for c := to; c != nil; c = prev[c] {
+			path = append([]*geonames_parse.CountryRec{c}, path...)
+		}
+	}
+	return
+}
+
+//	Breadth-first traversal of the neighbor graph from `iso2`, until `onCountry` returns `false`.
+func (me *Table) walk(iso2 string, onCountry func(c, from *geonames_parse.CountryRec, hop int) bool) {
+	start := me.ByIso2(iso2)
+	if start == nil {
+		return
+	}
+	type visit struct {
+		c, from *geonames_parse.CountryRec
+		hop     int
+	}
+	seen, queue := map[*geonames_parse.CountryRec]bool{start: true}, []visit{{start, nil, 0}}
+	for len(queue) > 0 {
+		v := queue[0]
+		if queue = queue[1:]; !onCountry(v.c, v.from, v.hop) {
+			return
+		}
+		for _, n := range me.Neighbors(v.c.Code.Iso2) {
+			if !seen[n] {
+				seen[n] = true
+				queue = append(queue, visit{n, v.c, v.hop + 1})
+			}
+		}
+	}
+}
+
+//	Returns all countries on the specified continent (eg. `EU`, see `ContinentNames`).
+func (me *Table) Continent(code string) (countries []*geonames_parse.CountryRec) {
+	code = strings.ToUpper(code)
+	for _, c := range me.Countries {
+		if c.Continent == code {
+			countries = append(countries, c)
+		}
+	}
+	return
+}
+
+//	Groups all countries by their continent code.
+func (me *Table) Continents() (continents map[string][]*geonames_parse.CountryRec) {
+	continents = map[string][]*geonames_parse.CountryRec{}
+	for _, c := range me.Countries {
+		continents[c.Continent] = append(continents[c.Continent], c)
+	}
+	return
+}
+
+//	Used by the generated `table.go`.
+func rec(iso2, iso3, isoNum, fips, name, capital string, areaSqKm, population int64, continent, tld, currencyCode, currencyName,
+	callingCode, postalFormat, postalRegex, languages string, id int64, neighbors, equivalentFipsCode string) (r geonames_parse.CountryRec) {
+	r.Code.Iso2, r.Code.Iso3, r.Code.IsoNum, r.Code.Fips = iso2, iso3, isoNum, fips
+	r.Name, r.Capital, r.AreaSqKm, r.Population, r.Continent, r.Tld = name, capital, areaSqKm, population, continent, tld
+	r.Currency.Code, r.Currency.Name, r.CallingCode = currencyCode, currencyName, callingCode
+	r.PostalCode.Format, r.PostalCode.Regex, r.Id, r.EquivalentFipsCode = postalFormat, postalRegex, id, equivalentFipsCode
+	r.Languages, r.Neighbors = split(languages), split(neighbors)
+	return
+}
+
+func split(s string) (vals []string) {
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			vals = append(vals, v)
+		}
+	}
+	return
+}
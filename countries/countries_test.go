@@ -0,0 +1,16 @@
+package geonames_countries
+
+import (
+	"testing"
+)
+
+//	Fails while the generated `table.go` is empty, that is, until `go generate` was run against a `countryInfo.txt` (see package doc).
+func TestDefault(t *testing.T) {
+	table := Default()
+	if len(table.Countries) < 200 {
+		t.Fatalf("Default() has %d countries: regenerate table.go via `GEONAMES_DUMPS=/path/to/dumps go generate ./countries`", len(table.Countries))
+	}
+	if de := table.ByIso2("DE"); de == nil || de.Code.Iso3 != "DEU" || de.Continent != "EU" {
+		t.Errorf("ByIso2(\"DE\") = %+v", de)
+	}
+}
@@ -0,0 +1,53 @@
+//	Generates `table.go` for the `countries` package from a local `countryInfo.txt`, failing if it contains no countries.
+//
+//	Run via `go generate`, with `$GEONAMES_DUMPS` set to the directory containing `countryInfo.txt`.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+func main() {
+	var (
+		buf          bytes.Buffer
+		dirPath, out string
+		src          []byte
+		count        int
+	)
+	flag.StringVar(&dirPath, "dir", "", "directory containing countryInfo.txt (via go generate: $GEONAMES_DUMPS)")
+	flag.StringVar(&out, "out", "table.go", "output file path")
+	flag.Parse()
+	if len(dirPath) == 0 {
+		log.Fatal("-dir not set: set $GEONAMES_DUMPS to the directory containing countryInfo.txt")
+	}
+
+	buf.WriteString("// Code generated by gen-table from countryInfo.txt; DO NOT EDIT.\n\npackage geonames_countries\n\n")
+	buf.WriteString("import (\n\t\"github.com/go-geo/geonames/parse-dumps\"\n)\n\nvar table = []geonames_parse.CountryRec{\n")
+	err := geonames_parse.NewIterator(dirPath).Countries(func(_ int, r *geonames_parse.CountryRec) {
+		count++
+		fmt.Fprintf(&buf, "\trec(%q, %q, %q, %q, %q, %q, %d, %d, %q, %q, %q, %q, %q, %q, %q, %q, %d, %q, %q),\n",
+			r.Code.Iso2, r.Code.Iso3, r.Code.IsoNum, r.Code.Fips, r.Name, r.Capital, r.AreaSqKm, r.Population, r.Continent, r.Tld,
+			r.Currency.Code, r.Currency.Name, r.CallingCode, r.PostalCode.Format, r.PostalCode.Regex, strings.Join(r.Languages, ","), r.Id, strings.Join(r.Neighbors, ","), r.EquivalentFipsCode)
+	})
+	buf.WriteString("}\n")
+	if err == nil && count == 0 {
+		err = fmt.Errorf("no countries in %s", filepath.Join(dirPath, "countryInfo.txt"))
+	}
+	if err == nil {
+		if src, err = format.Source(buf.Bytes()); err == nil {
+			err = os.WriteFile(out, src, 0644)
+		}
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
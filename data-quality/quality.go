@@ -0,0 +1,322 @@
+//	Validates raw `download.geonames.org/export/dump` files (via `parse-dumps` package) and reports the records that parsing and
+//	importing would silently drop or leave unresolved (including orphans referring to codes not defined in the other files),
+//	with counts and sample rows.
+package geonames_quality
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-geo/geonames/import-dumps"
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+//	Names of all checks, keys of `Report.Issues`.
+const (
+	PlaceInvalidCoordinates  = "places.invalid_coordinates"
+	PlaceMissingCoordinates  = "places.missing_coordinates"
+	PlacePlaceholderNames    = "places.placeholder_names"
+	PlaceNameless            = "places.nameless"
+	PlaceUnresolvedAdmin1    = "places.unresolved_admin1"
+	PlaceUnresolvedAdmin2    = "places.unresolved_admin2"
+	PlaceUnknownTimezones    = "places.unknown_timezones"
+	PlaceUnknownFeatures     = "places.unknown_features"
+	PlaceDuplicateIds        = "places.duplicate_ids"
+	PlaceUnknownCountries    = "places.unknown_countries"
+	PlaceUnknownCountries2   = "places.unknown_cc2"
+	PostalInvalidCoordinates = "postals.invalid_coordinates"
+	PostalMissingCoordinates = "postals.missing_coordinates"
+	PostalUnknownCountries   = "postals.unknown_countries"
+	AdminDuplicateCodes      = "admins.duplicate_codes"
+	AdminUnknownCountries    = "admins.unknown_countries"
+	CountryDuplicateCodes    = "countries.duplicate_codes"
+	CountryUnknownNeighbors  = "countries.unknown_neighbors"
+	TimezoneUnknownCountries = "timezones.unknown_countries"
+)
+
+var (
+	//	Maximum number of `Issue.Samples` retained per check
+	MaxSamples = 5
+
+	//	All checks in report order, with their descriptions. `Reference` checks find orphans: records referring to codes
+	//	not defined in the other files, which `geonames_import.Importer` references as `0` (or rejects if `Strict`).
+	Checks = []struct {
+		Name, Desc string
+		Reference  bool
+	}{
+		{PlaceInvalidCoordinates, "places with out-of-range coordinates (dropped by parsing)", false},
+		{PlaceMissingCoordinates, "places without coordinates (dropped by importing)", false},
+		{PlacePlaceholderNames, "places with placeholder names such as 'name unknown' (blanked by importing)", false},
+		{PlaceNameless, "places without any usable name (dropped by importing)", false},
+		{PlaceUnresolvedAdmin1, "places whose admin1 code is not in the admin1 codes", true},
+		{PlaceUnresolvedAdmin2, "places whose admin2 code is not in the admin2 codes", true},
+		{PlaceUnknownTimezones, "places whose time zone is not in the time zones", true},
+		{PlaceUnknownFeatures, "places whose feature class and code are not in the feature codes", true},
+		{PlaceUnknownCountries, "places whose country code is not in the countries", true},
+		{PlaceUnknownCountries2, "places with alternate country codes (cc2) not in the countries", true},
+		{PlaceDuplicateIds, "places whose geonameid occurred before", false},
+		{PostalInvalidCoordinates, "postal codes with out-of-range coordinates (dropped by parsing)", false},
+		{PostalMissingCoordinates, "postal codes without coordinates (dropped by importing)", false},
+		{PostalUnknownCountries, "postal codes whose country code is not in the countries", true},
+		{AdminDuplicateCodes, "admin divisions whose code occurred before", false},
+		{AdminUnknownCountries, "admin divisions whose code does not start with a country code in the countries", true},
+		{CountryDuplicateCodes, "countries whose ISO code occurred before", false},
+		{CountryUnknownNeighbors, "countries with neighbours not in the countries", true},
+		{TimezoneUnknownCountries, "time zones whose country code is not in the countries", true},
+	}
+)
+
+//	One offending record.
+type Sample struct {
+	//	The file name and 0-based record index (not counting comment and header lines)
+	File  string `json:"file"`
+	Index int    `json:"index"`
+
+	//	What exactly is wrong, if not obvious from `Record`
+	Detail string `json:"detail,omitempty"`
+
+	//	The record as written by `geonames_parse.Writer`
+	Record string `json:"record"`
+}
+
+//	The outcome of one check.
+type Issue struct {
+	//	Number of offending records
+	Count int `json:"count"`
+
+	//	Up to `MaxSamples` of them
+	Samples []Sample `json:"samples,omitempty"`
+}
+
+//	The result of `Check`.
+type Report struct {
+	//	Number of records read per file
+	Records map[string]int `json:"records"`
+
+	//	Names of files not found (and so not checked)
+	Missing []string `json:"missing,omitempty"`
+
+	//	All `Checks` by name, including those without findings (`Check` fills in all, callers may remove some)
+	Issues map[string]*Issue `json:"issues"`
+}
+
+//	Returns the number of all offending records.
+func (me *Report) Count() (count int) {
+	for _, issue := range me.Issues {
+		count += issue.Count
+	}
+	return
+}
+
+//	Returns the number of records found by the `Reference` checks.
+func (me *Report) Orphans() (count int) {
+	for _, c := range Checks {
+		if issue := me.Issues[c.Name]; c.Reference && issue != nil {
+			count += issue.Count
+		}
+	}
+	return
+}
+
+type checker struct {
+	*Report
+	geo     *geonames_parse.Iterator
+	invalid struct {
+		index  int
+		detail string
+	}
+
+	admin1    map[string]bool
+	admin2    map[string]bool
+	countries map[string]bool
+	features  map[string]bool
+	timezones map[string]bool
+}
+
+//	Reads all countries, time zones, feature codes, admin divisions, postal codes and places of `geo` and runs all `Checks` on them.
+//
+//	Files that do not exist are listed in `Report.Missing`, and the checks against their codes are skipped.
+//	Note that detecting duplicate place ids keeps all of them in memory.
+func Check(geo *geonames_parse.Iterator) (me *Report, err error) {
+	me = &Report{Records: map[string]int{}, Issues: map[string]*Issue{}}
+	for _, c := range Checks {
+		me.Issues[c.Name] = &Issue{}
+	}
+	geoCopy := *geo
+	chk := &checker{Report: me, geo: &geoCopy, admin1: map[string]bool{}, admin2: map[string]bool{}, countries: map[string]bool{}, features: map[string]bool{}, timezones: map[string]bool{}}
+	chk.invalid.index = -1
+	chk.geo.OnInvalidLonLat = func(_ string, index int, lon, lat string) {
+		chk.invalid.index, chk.invalid.detail = index, fmt.Sprintf("longitude %q, latitude %q", lon, lat)
+	}
+	fn := &chk.geo.FileNames
+	for _, step := range []struct {
+		fileName string
+		iterate  func() error
+	}{
+		{fn.Countries, chk.loadCountries},
+		{fn.Timezones, func() error { return chk.geo.Timezones(chk.onTimezone) }},
+		{fn.Features, func() error { return chk.geo.Features(chk.onFeature) }},
+		{fn.Admin1, func() error { return chk.geo.Admin1(chk.admin(fn.Admin1, chk.admin1)) }},
+		{fn.Admin2, func() error { return chk.geo.Admin2(chk.admin(fn.Admin2, chk.admin2)) }},
+		{fn.Postal, func() error { return chk.geo.PostalCodes(chk.onPostal) }},
+		{fn.Places, chk.places},
+	} {
+		if err = step.iterate(); os.IsNotExist(err) {
+			me.Missing, err = append(me.Missing, step.fileName), nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+func (me *checker) add(check, fileName string, index int, detail string, write func(*geonames_parse.Writer) error) {
+	issue := me.Issues[check]
+	if issue.Count++; len(issue.Samples) < MaxSamples {
+		var buf bytes.Buffer
+		w := geonames_parse.NewWriter(&buf)
+		if write(w) == nil && w.Flush() == nil {
+			issue.Samples = append(issue.Samples, Sample{File: fileName, Index: index, Detail: detail, Record: strings.TrimSuffix(buf.String(), "\n")})
+		}
+	}
+}
+
+//	Returns the raw coordinates reported via `OnInvalidLonLat` for the record at `index`, if any.
+func (me *checker) invalidLonLat(index int) (detail string, ok bool) {
+	if ok = me.invalid.index == index; ok {
+		detail, me.invalid.index = me.invalid.detail, -1
+	}
+	return
+}
+
+//	Returns whether `code` is a known country code, or no countries are known.
+func (me *checker) country(code string) bool {
+	return len(me.countries) == 0 || me.countries[code]
+}
+
+func (me *checker) onTimezone(i int, r *geonames_parse.TimezoneRec) {
+	fileName := me.geo.FileNames.Timezones
+	if me.Records[fileName]++; !me.country(r.CountryCode) {
+		me.add(TimezoneUnknownCountries, fileName, i, fmt.Sprintf("country %q", r.CountryCode), func(w *geonames_parse.Writer) error { return w.Timezone(r) })
+	}
+	me.timezones[r.TimezoneName] = true
+}
+
+func (me *checker) onFeature(_ int, r *geonames_parse.FeatureRec) {
+	me.Records[me.geo.FileNames.Features]++
+	me.features[r.Code] = true
+}
+
+//	Reads the countries twice: first to know all codes, then to check for duplicates and unknown neighbours.
+func (me *checker) loadCountries() (err error) {
+	fileName, seen := me.geo.FileNames.Countries, map[string]bool{}
+	if err = me.geo.Countries(func(_ int, r *geonames_parse.CountryRec) { me.countries[r.Code.Iso2] = true }); err == nil {
+		err = me.geo.Countries(func(i int, r *geonames_parse.CountryRec) {
+			me.Records[fileName]++
+			write := func(w *geonames_parse.Writer) error { return w.Country(r) }
+			if seen[r.Code.Iso2] {
+				me.add(CountryDuplicateCodes, fileName, i, "", write)
+			}
+			seen[r.Code.Iso2] = true
+			if unknown := me.unknownCountries(r.Neighbors); len(unknown) > 0 {
+				me.add(CountryUnknownNeighbors, fileName, i, fmt.Sprintf("neighbours %q", unknown), write)
+			}
+		})
+	}
+	return
+}
+
+func (me *checker) unknownCountries(codes []string) (unknown []string) {
+	for _, code := range codes {
+		if !me.country(code) {
+			unknown = append(unknown, code)
+		}
+	}
+	return
+}
+
+func (me *checker) admin(fileName string, codes map[string]bool) func(int, *geonames_parse.AdminRec) {
+	return func(i int, r *geonames_parse.AdminRec) {
+		write := func(w *geonames_parse.Writer) error { return w.Admin(r) }
+		if me.Records[fileName]++; codes[r.Code] {
+			me.add(AdminDuplicateCodes, fileName, i, "", write)
+		}
+		codes[r.Code] = true
+		if country, _, _ := strings.Cut(r.Code, "."); !me.country(country) {
+			me.add(AdminUnknownCountries, fileName, i, fmt.Sprintf("country %q", country), write)
+		}
+	}
+}
+
+func (me *checker) onPostal(i int, r *geonames_parse.PostalRec) {
+	fileName := me.geo.FileNames.Postal
+	me.Records[fileName]++
+	write := func(w *geonames_parse.Writer) error { return w.Postal(r) }
+	if detail, ok := me.invalidLonLat(i); ok {
+		me.add(PostalInvalidCoordinates, fileName, i, detail, write)
+	} else if len(r.LonLat) != 2 {
+		me.add(PostalMissingCoordinates, fileName, i, "", write)
+	}
+	if !me.country(r.CountryCode) {
+		me.add(PostalUnknownCountries, fileName, i, fmt.Sprintf("country %q", r.CountryCode), write)
+	}
+}
+
+func (me *checker) places() error {
+	fileName, ids := me.geo.FileNames.Places, map[int64]bool{}
+	return me.geo.Places(func(i int, r *geonames_parse.PlaceRec) {
+		me.Records[fileName]++
+		write := func(w *geonames_parse.Writer) error { return w.Place(r) }
+		add := func(check, detail string) { me.add(check, fileName, i, detail, write) }
+
+		if detail, ok := me.invalidLonLat(i); ok {
+			add(PlaceInvalidCoordinates, detail)
+		} else if len(r.LonLat) != 2 {
+			add(PlaceMissingCoordinates, "")
+		}
+
+		var placeholder string
+		usable := false
+		for _, name := range append([]string{r.Name, r.NameAscii}, r.NamesAlt...) {
+			if !geonames_import.IsPlaceholderName(name) {
+				usable = usable || len(name) > 0
+			} else if len(placeholder) == 0 && (name == r.Name || name == r.NameAscii) {
+				placeholder = name
+			}
+		}
+		if len(placeholder) > 0 {
+			add(PlacePlaceholderNames, fmt.Sprintf("name %q", placeholder))
+		}
+		if !usable {
+			add(PlaceNameless, "")
+		}
+
+		if code1 := r.Admin.Code1; len(code1) > 0 && code1 != "00" {
+			if key := r.Country.Code + "." + code1; len(me.admin1) > 0 && !me.admin1[key] {
+				add(PlaceUnresolvedAdmin1, fmt.Sprintf("admin1 %q", key))
+			} else if code2 := r.Admin.Code2; len(me.admin2) > 0 && len(code2) > 0 && code2 != "00" {
+				if key += "." + code2; !me.admin2[key] {
+					add(PlaceUnresolvedAdmin2, fmt.Sprintf("admin2 %q", key))
+				}
+			}
+		}
+		if len(me.timezones) > 0 && len(r.TimezoneName) > 0 && !me.timezones[r.TimezoneName] {
+			add(PlaceUnknownTimezones, fmt.Sprintf("time zone %q", r.TimezoneName))
+		}
+		if code := r.Feature.Class + "." + r.Feature.Code; len(me.features) > 0 && len(r.Feature.Class) > 0 && !me.features[code] {
+			add(PlaceUnknownFeatures, fmt.Sprintf("feature %q", code))
+		}
+		if len(r.Country.Code) > 0 && !me.country(r.Country.Code) {
+			add(PlaceUnknownCountries, fmt.Sprintf("country %q", r.Country.Code))
+		}
+		if unknown := me.unknownCountries(r.Country.CodesAlt); len(unknown) > 0 {
+			add(PlaceUnknownCountries2, fmt.Sprintf("cc2 %q", unknown))
+		}
+		if ids[r.Id] {
+			add(PlaceDuplicateIds, "")
+		}
+		ids[r.Id] = true
+	})
+}
@@ -0,0 +1,114 @@
+package geonames_quality
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+const testdata = "../parse-dumps/testdata"
+
+//	Runs `Check` and compares the `Issue.Count`s against `counts` (all other checks expecting none).
+func check(t *testing.T, dirPath string, missing []string, counts map[string]int) *Report {
+	report, err := Check(geonames_parse.NewIterator(dirPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report.Missing, missing) {
+		t.Errorf("Missing: %q, expected %q", report.Missing, missing)
+	}
+	for _, c := range Checks {
+		if issue := report.Issues[c.Name]; issue == nil || issue.Count != counts[c.Name] || len(issue.Samples) > MaxSamples {
+			t.Errorf("%s: %+v, expected %d", c.Name, issue, counts[c.Name])
+		}
+	}
+	return report
+}
+
+//	Copies the `parse-dumps` testdata files except `skip` into a new temporary directory, then adds `add`.
+func copyTestdata(t *testing.T, skip string, add map[string]string) (dirPath string) {
+	dirPath = t.TempDir()
+	entries, err := os.ReadDir(testdata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if name := entry.Name(); name != skip && !entry.IsDir() {
+			data, err := os.ReadFile(filepath.Join(testdata, name))
+			if err == nil {
+				data = append(data, add[name]...)
+				err = os.WriteFile(filepath.Join(dirPath, name), data, 0644)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for name, data := range add {
+		if _, err = os.Stat(filepath.Join(dirPath, name)); os.IsNotExist(err) {
+			err = os.WriteFile(filepath.Join(dirPath, name), []byte(data), 0644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return
+}
+
+func TestCheck(t *testing.T) {
+	report := check(t, testdata, []string{"featureCodes_en.txt"}, map[string]int{
+		AdminUnknownCountries:    2,
+		CountryUnknownNeighbors:  2,
+		PlaceUnknownCountries2:   1,
+		PlaceUnresolvedAdmin1:    1,
+		PostalMissingCoordinates: 1,
+		PostalUnknownCountries:   2,
+		TimezoneUnknownCountries: 3,
+	})
+	if orphans := report.Orphans(); orphans != 11 {
+		t.Errorf("Orphans: %d, expected 11", orphans)
+	}
+	if records := report.Records["allCountries.txt"]; records != 4 {
+		t.Errorf("Records: %d places, expected 4", records)
+	}
+	if sample := report.Issues[PlaceUnresolvedAdmin1].Samples[0]; sample.Index != 1 || sample.Detail != `admin1 "DE.16"` {
+		t.Errorf("%s: sample %+v", PlaceUnresolvedAdmin1, sample)
+	}
+}
+
+func TestCheckMissingAdmin2(t *testing.T) {
+	//	`München` still refers to `DE.02.091`, which is only unresolved if `admin2Codes.txt` exists
+	check(t, copyTestdata(t, "admin2Codes.txt", map[string]string{
+		"admin1CodesASCII.txt": "DE.16\tBerlin\tBerlin\t2950157\n",
+	}), []string{"featureCodes_en.txt", "admin2Codes.txt"}, map[string]int{
+		AdminUnknownCountries:    1,
+		CountryUnknownNeighbors:  2,
+		PlaceUnknownCountries2:   1,
+		PostalMissingCoordinates: 1,
+		PostalUnknownCountries:   2,
+		TimezoneUnknownCountries: 3,
+	})
+}
+
+func TestCheckFeatures(t *testing.T) {
+	//	`Zugspitze` is a `T.MT`, the added place has no feature class (nor code)
+	report := check(t, copyTestdata(t, "", map[string]string{
+		"featureCodes_en.txt": "P.PPLA\tseat of a first-order administrative division\t\nP.PPLC\tcapital of a political entity\t\nH.SEA\tsea\t\n",
+		"allCountries.txt":    "2921044\tGermany\tGermany\t\t51.5\t10.5\t\t\tDE\t\t00\t\t\t\t82927922\t\t303\tEurope/Berlin\t2024-01-01\n",
+	}), nil, map[string]int{
+		AdminUnknownCountries:    2,
+		CountryUnknownNeighbors:  2,
+		PlaceUnknownCountries2:   1,
+		PlaceUnknownFeatures:     1,
+		PlaceUnresolvedAdmin1:    1,
+		PostalMissingCoordinates: 1,
+		PostalUnknownCountries:   2,
+		TimezoneUnknownCountries: 3,
+	})
+	if sample := report.Issues[PlaceUnknownFeatures].Samples[0]; sample.Detail != `feature "T.MT"` {
+		t.Errorf("%s: sample %+v", PlaceUnknownFeatures, sample)
+	}
+}
@@ -0,0 +1,50 @@
+package geonames_quality
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+//	Writes `me` as a human-readable report to `w`: the records read per file, the missing files, the count of each check
+//	in `Issues`, and then the samples of all checks with findings.
+func (me *Report) WriteText(w io.Writer) (err error) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILE\tRECORDS")
+	files := make([]string, 0, len(me.Records))
+	for fileName := range me.Records {
+		files = append(files, fileName)
+	}
+	sort.Strings(files)
+	for _, fileName := range files {
+		fmt.Fprintf(tw, "%s\t%d\n", fileName, me.Records[fileName])
+	}
+	for _, fileName := range me.Missing {
+		fmt.Fprintf(tw, "%s\tmissing\n", fileName)
+	}
+	fmt.Fprintln(tw, "\nCHECK\tCOUNT\tDESCRIPTION")
+	for _, c := range Checks {
+		if issue := me.Issues[c.Name]; issue != nil {
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", c.Name, issue.Count, c.Desc)
+		}
+	}
+	if err = tw.Flush(); err != nil {
+		return
+	}
+	for _, c := range Checks {
+		if issue := me.Issues[c.Name]; issue != nil && len(issue.Samples) > 0 {
+			fmt.Fprintf(w, "\n%s (%d of %d):\n", c.Name, len(issue.Samples), issue.Count)
+			for _, s := range issue.Samples {
+				fmt.Fprintf(w, "  %s #%d", s.File, s.Index)
+				if len(s.Detail) > 0 {
+					fmt.Fprintf(w, ": %s", s.Detail)
+				}
+				if _, err = fmt.Fprintf(w, "\n    %q\n", s.Record); err != nil {
+					return
+				}
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,377 @@
+//	Compares two sets of `download.geonames.org/export/dump` files (via `parse-dumps` package) record by record, reporting added,
+//	removed and modified places, postal codes and admin divisions with their field-level changes.
+package geonames_diff
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+const (
+	Added    = "added"
+	Removed  = "removed"
+	Modified = "modified"
+)
+
+//	Controls `Compare`.
+type Options struct {
+	//	Fields not to compare (eg. `modification_date`), see `PlaceFields`, `PostalFields` and `AdminFields`
+	Ignore []string
+
+	//	Maximum number of `Changes` retained per `Section` (all are still counted), or `0` for all
+	MaxChanges int
+
+	//	Whether to skip comparing the (large) postal-codes files
+	SkipPostals bool
+}
+
+var (
+	//	Compared fields of places, keyed by `geonameid`
+	PlaceFields = []string{"name", "asciiname", "alternatenames", "latitude", "longitude", "feature_class", "feature_code", "country_code",
+		"cc2", "admin1_code", "admin2_code", "admin3_code", "admin4_code", "population", "elevation", "timezone", "modification_date"}
+
+	//	Compared fields of postal codes, keyed by `country_code`, `postal_code` and `place_name`
+	PostalFields = []string{"admin_name1", "admin_code1", "admin_name2", "admin_code2", "admin_name3", "admin_code3", "latitude", "longitude", "accuracy"}
+
+	//	Compared fields of admin divisions, keyed by their full code (eg. `US.CA.037`)
+	AdminFields = []string{"name", "asciiname", "geonameid"}
+)
+
+//	One field of a modified record.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+//	One added, removed or modified record.
+type Change struct {
+	//	`Added`, `Removed` or `Modified`
+	Kind string `json:"kind"`
+
+	//	Identifies the record across both sets of files
+	Key string `json:"key"`
+
+	//	The record's country code, name (except for postal codes, whose `Key` includes it) and feature code (eg. `P.PPLC`, or `ADM1`
+	//	or `ADM2` for admins), from the newer files unless `Removed`
+	Country string `json:"country,omitempty"`
+	Feature string `json:"feature,omitempty"`
+	Name    string `json:"name,omitempty"`
+
+	//	The changed fields if `Modified`
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+//	Numbers of changed records.
+type Counts struct {
+	Added    int `json:"added"`
+	Removed  int `json:"removed"`
+	Modified int `json:"modified"`
+}
+
+func (me *Counts) add(kind string) {
+	switch kind {
+	case Added:
+		me.Added++
+	case Removed:
+		me.Removed++
+	case Modified:
+		me.Modified++
+	}
+}
+
+//	The changes to one kind of record.
+type Section struct {
+	//	Number of records in the older and newer files
+	Old int `json:"old"`
+	New int `json:"new"`
+
+	Counts
+
+	//	`Counts` per country code and per feature code (or admin level)
+	ByCountry map[string]*Counts `json:"by_country,omitempty"`
+	ByFeature map[string]*Counts `json:"by_feature,omitempty"`
+
+	//	Number of `Modified` records per changed field
+	ByField map[string]int `json:"by_field,omitempty"`
+
+	//	Up to `Options.MaxChanges` changes: for places all by `geonameid`, otherwise `Modified` and `Added` ones in the order of the newer
+	//	files, then `Removed` ones by `Key`
+	Changes []Change `json:"changes,omitempty"`
+}
+
+func newSection() *Section {
+	return &Section{ByCountry: map[string]*Counts{}, ByFeature: map[string]*Counts{}, ByField: map[string]int{}}
+}
+
+func (me *Section) add(change *Change, maxChanges int) {
+	me.Counts.add(change.Kind)
+	countBy(me.ByCountry, change.Country, change.Kind)
+	countBy(me.ByFeature, change.Feature, change.Kind)
+	for _, f := range change.Fields {
+		me.ByField[f.Field]++
+	}
+	if maxChanges <= 0 || len(me.Changes) < maxChanges {
+		me.Changes = append(me.Changes, *change)
+	}
+}
+
+func countBy(counts map[string]*Counts, key, kind string) {
+	if len(key) > 0 {
+		if counts[key] == nil {
+			counts[key] = &Counts{}
+		}
+		counts[key].add(kind)
+	}
+}
+
+//	The result of `Compare`.
+type Report struct {
+	Places  *Section `json:"places"`
+	Postals *Section `json:"postals,omitempty"`
+	Admins  *Section `json:"admins"`
+}
+
+//	Compares all places, postal codes and admin divisions of `older` and `newer`, each kind of record in its own goroutine.
+//
+//	Places must be ordered by `geonameid` (as in `allCountries.txt` and its per-country extracts) in both sets of files, and are
+//	streamed side by side. The older admin divisions and postal codes are held in memory (as field values) while the newer ones are
+//	streamed past them: that is about 1 GB for a complete `allCountries.zip` of postal codes, unless `opts.SkipPostals`.
+func Compare(older, newer *geonames_parse.Iterator, opts *Options) (me *Report, err error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	me = &Report{Places: newSection(), Admins: newSection()}
+	var (
+		wait sync.WaitGroup
+		mu   sync.Mutex
+	)
+	run := func(fn func() error) {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			if e := fn(); e != nil {
+				mu.Lock()
+				if err == nil {
+					err = e
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	run(func() error {
+		return compareOrdered(me.Places, opts, PlaceFields, older.Places, newer.Places, func(r *geonames_parse.PlaceRec) int64 {
+			return r.Id
+		}, func(r *geonames_parse.PlaceRec) (string, Change, []string) {
+			lon, lat := lonLat(r.LonLat)
+			return itoa(r.Id), Change{Country: r.Country.Code, Feature: r.Feature.Class + "." + r.Feature.Code, Name: r.Name},
+				[]string{r.Name, r.NameAscii, strings.Join(r.NamesAlt, ","), lat, lon, r.Feature.Class, r.Feature.Code, r.Country.Code,
+					strings.Join(r.Country.CodesAlt, ","), r.Admin.Code1, r.Admin.Code2, r.Admin.Code3, r.Admin.Code4, itoa(r.Population),
+					itoa(r.Elevation), r.TimezoneName, date(r)}
+		})
+	})
+	run(func() error {
+		return compare(me.Admins, opts, AdminFields, older.AdminAll, newer.AdminAll, func(r *geonames_parse.AdminRec) (string, Change, []string) {
+			country, _, _ := strings.Cut(r.Code, ".")
+			return r.Code, Change{Country: country, Feature: "ADM" + strconv.Itoa(strings.Count(r.Code, ".")), Name: r.Name},
+				[]string{r.Name, r.NameAscii, itoa(r.Id)}
+		})
+	})
+	if !opts.SkipPostals {
+		me.Postals = newSection()
+		run(func() error {
+			return compare(me.Postals, opts, PostalFields, older.PostalCodes, newer.PostalCodes, func(r *geonames_parse.PostalRec) (string, Change, []string) {
+				lon, lat := lonLat(r.LonLat)
+				return r.CountryCode + " " + r.PostalCode + " " + r.PlaceName, Change{Country: r.CountryCode},
+					[]string{r.Admin.Name1, r.Admin.Code1, r.Admin.Name2, r.Admin.Code2, r.Admin.Name3, r.Admin.Code3, lat, lon, itoa(r.Accuracy)}
+			})
+		})
+	}
+	if wait.Wait(); err != nil {
+		me = nil
+	}
+	return
+}
+
+type oldRec struct {
+	change Change
+
+	//	The record's field values, tab-separated (as parsed values never contain tabs)
+	values string
+}
+
+//	Compares the records of `iterOld` and `iterNew`, identified by the key returned from `describe` (duplicate keys are numbered),
+//	and adds their changes to `sec`.
+func compare[T any](sec *Section, opts *Options, fields []string, iterOld, iterNew func(func(int, *T)) error, describe func(*T) (string, Change, []string)) (err error) {
+	ignore := ignored(fields, opts)
+	olds, dupes := map[string]*oldRec{}, map[string]int{}
+	uniqueKey := func(key string) string {
+		if n := dupes[key]; n > 0 {
+			dupes[key] = n + 1
+			return key + " #" + strconv.Itoa(n+1)
+		}
+		dupes[key] = 1
+		return key
+	}
+	if err = iterOld(func(_ int, r *T) {
+		key, change, values := describe(r)
+		change.Key = uniqueKey(key)
+		olds[change.Key] = &oldRec{change: change, values: strings.Join(values, "\t")}
+		sec.Old++
+	}); err != nil {
+		return
+	}
+	dupes = map[string]int{}
+	if err = iterNew(func(_ int, r *T) {
+		key, change, values := describe(r)
+		change.Key = uniqueKey(key)
+		sec.New++
+		if old := olds[change.Key]; old == nil {
+			change.Kind = Added
+			sec.add(&change, opts.MaxChanges)
+		} else {
+			delete(olds, change.Key)
+			modified(sec, opts, &change, fields, ignore, strings.Split(old.values, "\t"), values)
+		}
+	}); err != nil {
+		return
+	}
+	removed := make([]string, 0, len(olds))
+	for key := range olds {
+		removed = append(removed, key)
+	}
+	sort.Strings(removed)
+	for _, key := range removed {
+		change := olds[key].change
+		change.Kind = Removed
+		sec.add(&change, opts.MaxChanges)
+	}
+	return
+}
+
+type orderedRec struct {
+	id     int64
+	change Change
+	values []string
+}
+
+//	Compares the records of `iterOld` and `iterNew`, both ordered by ascending `id`, and adds their changes to `sec`. The older records
+//	are streamed (via a goroutine) alongside the newer ones rather than held in memory, failing on the first one out of order.
+func compareOrdered[T any](sec *Section, opts *Options, fields []string, iterOld, iterNew func(func(int, *T)) error, id func(*T) int64, describe func(*T) (string, Change, []string)) (err error) {
+	var (
+		errOld          error
+		lastOld, lastId int64
+		ignore          = ignored(fields, opts)
+		olds, done      = make(chan *orderedRec, 1024), make(chan struct{})
+	)
+	defer close(done)
+	go func() {
+		defer close(olds)
+		errOld = iterOld(func(_ int, r *T) {
+			key, change, values := describe(r)
+			change.Key = key
+			select {
+			case olds <- &orderedRec{id: id(r), change: change, values: values}:
+			case <-done:
+			}
+		})
+	}()
+	next := func() (old *orderedRec) {
+		if old = <-olds; old != nil {
+			sec.Old++
+			if old.id <= lastOld {
+				err, old = fmt.Errorf("older records not ordered by id: %d follows %d", old.id, lastOld), nil
+			} else {
+				lastOld = old.id
+			}
+		}
+		return
+	}
+	removed := func(old *orderedRec) {
+		old.change.Kind = Removed
+		sec.add(&old.change, opts.MaxChanges)
+	}
+
+	old := next()
+	if err != nil {
+		return
+	}
+	errNew := iterNew(func(_ int, r *T) {
+		if err != nil {
+			return
+		} else if newId := id(r); newId <= lastId {
+			err = fmt.Errorf("newer records not ordered by id: %d follows %d", newId, lastId)
+			return
+		} else {
+			lastId = newId
+		}
+		sec.New++
+		for ; old != nil && old.id < lastId; old = next() {
+			removed(old)
+		}
+		key, change, values := describe(r)
+		change.Key = key
+		if old == nil || old.id > lastId {
+			change.Kind = Added
+			sec.add(&change, opts.MaxChanges)
+		} else {
+			modified(sec, opts, &change, fields, ignore, old.values, values)
+			old = next()
+		}
+	})
+	if err == nil && errNew == nil {
+		for ; old != nil; old = next() {
+			removed(old)
+		}
+	}
+	if err == nil {
+		if err = errNew; err == nil {
+			err = errOld
+		}
+	}
+	return
+}
+
+//	Sets `change.Fields` to those of `values` differing from `oldValues`, if any, and then adds the `Modified` `change` to `sec`.
+func modified(sec *Section, opts *Options, change *Change, fields []string, ignore []bool, oldValues, values []string) {
+	for i := range values {
+		if values[i] != oldValues[i] && !ignore[i] {
+			change.Fields = append(change.Fields, FieldChange{Field: fields[i], Old: oldValues[i], New: values[i]})
+		}
+	}
+	if len(change.Fields) > 0 {
+		change.Kind = Modified
+		sec.add(change, opts.MaxChanges)
+	}
+}
+
+func ignored(fields []string, opts *Options) (ignore []bool) {
+	ignore = make([]bool, len(fields))
+	for i, f := range fields {
+		for _, ign := range opts.Ignore {
+			ignore[i] = ignore[i] || ign == f
+		}
+	}
+	return
+}
+
+func date(r *geonames_parse.PlaceRec) string {
+	if r.Modified.IsZero() {
+		return ""
+	}
+	return r.Modified.Format("2006-01-02")
+}
+
+func itoa(i int64) string { return strconv.FormatInt(i, 10) }
+
+func lonLat(ll []float64) (lon, lat string) {
+	if len(ll) == 2 {
+		lon, lat = strconv.FormatFloat(ll[0], 'f', -1, 64), strconv.FormatFloat(ll[1], 'f', -1, 64)
+	}
+	return
+}
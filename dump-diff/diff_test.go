@@ -0,0 +1,90 @@
+package geonames_diff
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+func compareTestdata(t *testing.T, opts *Options) *Report {
+	report, err := Compare(geonames_parse.NewIterator(filepath.Join("testdata", "old")), geonames_parse.NewIterator(filepath.Join("testdata", "new")), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func checkSection(t *testing.T, name string, sec *Section, old, new int, counts Counts, changes []Change) {
+	if sec.Old != old || sec.New != new {
+		t.Errorf("%s: %d -> %d records, expected %d -> %d", name, sec.Old, sec.New, old, new)
+	}
+	if sec.Counts != counts {
+		t.Errorf("%s: counts %+v, expected %+v", name, sec.Counts, counts)
+	}
+	if !reflect.DeepEqual(sec.Changes, changes) {
+		t.Errorf("%s: changes\n%+v\nexpected\n%+v", name, sec.Changes, changes)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	report := compareTestdata(t, nil)
+	checkSection(t, "places", report.Places, 4, 5, Counts{Added: 2, Removed: 1, Modified: 2}, []Change{
+		{Kind: Added, Key: "2657896", Country: "CH", Feature: "P.PPLA", Name: "Zurich"},
+		{Kind: Modified, Key: "2867714", Country: "DE", Feature: "P.PPLA", Name: "Munich", Fields: []FieldChange{
+			{Field: "population", Old: "1260391", New: "1262000"}, {Field: "modification_date", Old: "2023-10-26", New: "2024-05-02"}}},
+		{Kind: Removed, Key: "2960313", Country: "DE", Feature: "T.PK", Name: "Zugspitze"},
+		{Kind: Added, Key: "3020251", Feature: "H.SEA", Name: "North Sea"},
+		{Kind: Modified, Key: "3067696", Country: "CZ", Feature: "P.PPLC", Name: "Praha", Fields: []FieldChange{
+			{Field: "name", Old: "Prague", New: "Praha"}, {Field: "alternatenames", Old: "Praha,Prag", New: "Prag"}}},
+	})
+	checkSection(t, "admins", report.Admins, 3, 4, Counts{Added: 1, Modified: 1}, []Change{
+		{Kind: Modified, Key: "DE.02", Country: "DE", Feature: "ADM1", Name: "Bayern", Fields: []FieldChange{
+			{Field: "name", Old: "Bavaria", New: "Bayern"}}},
+		{Kind: Added, Key: "DE.02.092", Country: "DE", Feature: "ADM2", Name: "Lower Bavaria"},
+	})
+	checkSection(t, "postals", report.Postals, 2, 2, Counts{Added: 1, Removed: 1, Modified: 1}, []Change{
+		{Kind: Modified, Key: "DE 80331 München", Country: "DE", Fields: []FieldChange{{Field: "accuracy", Old: "4", New: "6"}}},
+		{Kind: Added, Key: "AD AD100 Canillo", Country: "AD"},
+		{Kind: Removed, Key: "DE 01067 Dresden", Country: "DE"},
+	})
+	if by := report.Places.ByField; by["population"] != 1 || by["name"] != 1 || by["modification_date"] != 1 {
+		t.Errorf("places by field: %v", by)
+	}
+}
+
+func TestCompareIgnore(t *testing.T) {
+	report := compareTestdata(t, &Options{Ignore: []string{"population", "modification_date"}, MaxChanges: 1, SkipPostals: true})
+	checkSection(t, "places", report.Places, 4, 5, Counts{Added: 2, Removed: 1, Modified: 1}, []Change{
+		{Kind: Added, Key: "2657896", Country: "CH", Feature: "P.PPLA", Name: "Zurich"},
+	})
+	if report.Postals != nil {
+		t.Errorf("postals compared despite SkipPostals")
+	}
+}
+
+func TestCompareUnordered(t *testing.T) {
+	dirPath := t.TempDir()
+	for _, fileName := range []string{"admin1CodesASCII.txt", "admin2Codes.txt", "zip_allCountries.txt"} {
+		if data, err := os.ReadFile(filepath.Join("testdata", "new", fileName)); err != nil {
+			t.Fatal(err)
+		} else if err = os.WriteFile(filepath.Join(dirPath, fileName), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join("testdata", "new", "allCountries.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	lines[0], lines[1] = lines[1], lines[0]
+	if err = os.WriteFile(filepath.Join(dirPath, "allCountries.txt"), []byte(strings.Join(lines, "")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Compare(geonames_parse.NewIterator(filepath.Join("testdata", "old")), geonames_parse.NewIterator(dirPath), nil); err == nil {
+		t.Error("expected an error for places not ordered by geonameid")
+	}
+}
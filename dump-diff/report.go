@@ -0,0 +1,86 @@
+package geonames_diff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+//	Writes `me` as a human-readable report to `w`: per kind of record, the totals, a table of `Counts` per country and feature code,
+//	the numbers of changes per field, and then all retained `Changes`, marked `+` (added), `-` (removed) or `~` (modified).
+func (me *Report) WriteText(w io.Writer) (err error) {
+	for _, s := range []struct {
+		title string
+		sec   *Section
+	}{{"PLACES", me.Places}, {"POSTAL CODES", me.Postals}, {"ADMIN DIVISIONS", me.Admins}} {
+		if s.sec != nil {
+			if err = s.sec.writeText(w, s.title); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (me *Section) writeText(w io.Writer, title string) (err error) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s: %d -> %d records, %d added, %d removed, %d modified\n", title, me.Old, me.New, me.Added, me.Removed, me.Modified)
+	for _, by := range []struct {
+		title  string
+		counts map[string]*Counts
+	}{{"COUNTRY", me.ByCountry}, {"FEATURE", me.ByFeature}} {
+		if len(by.counts) > 0 {
+			fmt.Fprintf(tw, "\n%s\tADDED\tREMOVED\tMODIFIED\n", by.title)
+			for _, key := range sortedKeys(by.counts) {
+				c := by.counts[key]
+				fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", key, c.Added, c.Removed, c.Modified)
+			}
+		}
+	}
+	if len(me.ByField) > 0 {
+		fmt.Fprint(tw, "\nFIELD\tMODIFIED\n")
+		for _, field := range sortedKeys(me.ByField) {
+			fmt.Fprintf(tw, "%s\t%d\n", field, me.ByField[field])
+		}
+	}
+	if err = tw.Flush(); err != nil {
+		return
+	}
+	if len(me.Changes) > 0 {
+		fmt.Fprintln(w)
+	}
+	marks := map[string]string{Added: "+", Removed: "-", Modified: "~"}
+	for _, c := range me.Changes {
+		var about []string
+		for _, s := range []string{c.Country, c.Feature} {
+			if len(s) > 0 {
+				about = append(about, s)
+			}
+		}
+		if len(c.Name) > 0 {
+			c.Key += " " + c.Name
+		}
+		if _, err = fmt.Fprintf(w, "%s %s (%s)\n", marks[c.Kind], c.Key, strings.Join(about, ", ")); err != nil {
+			return
+		}
+		for _, f := range c.Fields {
+			fmt.Fprintf(w, "    %s: %q -> %q\n", f.Field, f.Old, f.New)
+		}
+	}
+	if total := me.Added + me.Removed + me.Modified; total > len(me.Changes) {
+		fmt.Fprintf(w, "... and %d more changes\n", total-len(me.Changes))
+	}
+	_, err = fmt.Fprintln(w)
+	return
+}
+
+func sortedKeys[V any](m map[string]V) (keys []string) {
+	keys = make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
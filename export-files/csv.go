@@ -0,0 +1,429 @@
+package geonames_export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-geo/geonames/countries"
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+var (
+	//	Human-readable names of the single-letter feature classes, used by the `feature_class_name` columns
+	FeatureClassNames = map[string]string{
+		"A": "Country, state, region",
+		"H": "Stream, lake",
+		"L": "Parks, area",
+		"P": "City, village",
+		"R": "Road, railroad",
+		"S": "Spot, building, farm",
+		"T": "Mountain, hill, rock",
+		"U": "Undersea",
+		"V": "Forest, heath",
+	}
+
+	//	`CsvWriter` columns for admin1CodesASCII.txt and admin2Codes.txt. All `Csv*Fields` are in default column order, and those marked `Default` are written if no columns are selected.
+	CsvAdminFields = []CsvField[geonames_parse.AdminRec]{
+		{"code", true, func(r *geonames_parse.AdminRec, _ *Names) string { return r.Code }},
+		{"name", true, func(r *geonames_parse.AdminRec, _ *Names) string { return r.Name }},
+		{"asciiname", false, func(r *geonames_parse.AdminRec, _ *Names) string { return r.NameAscii }},
+		{"geonameid", false, func(r *geonames_parse.AdminRec, _ *Names) string { return itoa(r.Id) }},
+		{"level", false, func(r *geonames_parse.AdminRec, _ *Names) string { return itoa(int64(strings.Count(r.Code, "."))) }},
+		{"country_code", false, func(r *geonames_parse.AdminRec, _ *Names) string { return firstSegment(r.Code) }},
+		{"country", true, func(r *geonames_parse.AdminRec, n *Names) string { return n.Country(firstSegment(r.Code)) }},
+		{"parent", true, func(r *geonames_parse.AdminRec, n *Names) string {
+			if strings.Count(r.Code, ".") > 1 {
+				return n.Admin(r.Code[:strings.LastIndex(r.Code, ".")])
+			}
+			return ""
+		}},
+	}
+
+	//	`CsvWriter` columns for alternateNames.txt
+	CsvAlternateNameFields = []CsvField[geonames_parse.AlternateNameRec]{
+		{"alternatenameid", true, func(r *geonames_parse.AlternateNameRec, _ *Names) string { return itoa(r.Id) }},
+		{"geonameid", true, func(r *geonames_parse.AlternateNameRec, _ *Names) string { return itoa(r.PlaceId) }},
+		{"isolanguage", true, func(r *geonames_parse.AlternateNameRec, _ *Names) string { return r.Language }},
+		{"alternate_name", true, func(r *geonames_parse.AlternateNameRec, _ *Names) string { return r.Name }},
+		{"preferred", true, func(r *geonames_parse.AlternateNameRec, _ *Names) string { return flag(r.Preferred) }},
+		{"short", true, func(r *geonames_parse.AlternateNameRec, _ *Names) string { return flag(r.Short) }},
+		{"colloquial", true, func(r *geonames_parse.AlternateNameRec, _ *Names) string { return flag(r.Colloquial) }},
+		{"historic", true, func(r *geonames_parse.AlternateNameRec, _ *Names) string { return flag(r.Historic) }},
+	}
+
+	//	`CsvWriter` columns for countryInfo.txt
+	CsvCountryFields = []CsvField[geonames_parse.CountryRec]{
+		{"iso", true, func(r *geonames_parse.CountryRec, _ *Names) string { return r.Code.Iso2 }},
+		{"iso3", false, func(r *geonames_parse.CountryRec, _ *Names) string { return r.Code.Iso3 }},
+		{"iso_numeric", false, func(r *geonames_parse.CountryRec, _ *Names) string { return r.Code.IsoNum }},
+		{"fips", false, func(r *geonames_parse.CountryRec, _ *Names) string { return r.Code.Fips }},
+		{"name", true, func(r *geonames_parse.CountryRec, _ *Names) string { return r.Name }},
+		{"capital", true, func(r *geonames_parse.CountryRec, _ *Names) string { return r.Capital }},
+		{"area_sq_km", true, func(r *geonames_parse.CountryRec, _ *Names) string { return itoa(r.AreaSqKm) }},
+		{"population", true, func(r *geonames_parse.CountryRec, _ *Names) string { return itoa(r.Population) }},
+		{"continent_code", false, func(r *geonames_parse.CountryRec, _ *Names) string { return r.Continent }},
+		{"continent", true, func(r *geonames_parse.CountryRec, _ *Names) string {
+			return geonames_countries.ContinentNames[r.Continent]
+		}},
+		{"tld", false, func(r *geonames_parse.CountryRec, _ *Names) string { return r.Tld }},
+		{"currency_code", false, func(r *geonames_parse.CountryRec, _ *Names) string { return r.Currency.Code }},
+		{"currency", true, func(r *geonames_parse.CountryRec, _ *Names) string { return r.Currency.Name }},
+		{"phone", false, func(r *geonames_parse.CountryRec, _ *Names) string { return r.CallingCode }},
+		{"postal_code_format", false, func(r *geonames_parse.CountryRec, _ *Names) string { return r.PostalCode.Format }},
+		{"postal_code_regex", false, func(r *geonames_parse.CountryRec, _ *Names) string { return r.PostalCode.Regex }},
+		{"languages", true, func(r *geonames_parse.CountryRec, _ *Names) string { return strings.Join(r.Languages, ",") }},
+		{"geonameid", false, func(r *geonames_parse.CountryRec, _ *Names) string { return itoa(r.Id) }},
+		{"neighbour_codes", false, func(r *geonames_parse.CountryRec, _ *Names) string { return strings.Join(r.Neighbors, ",") }},
+		{"neighbours", true, func(r *geonames_parse.CountryRec, n *Names) string {
+			names := make([]string, len(r.Neighbors))
+			for i, iso := range r.Neighbors {
+				if names[i] = n.Country(iso); len(names[i]) == 0 {
+					names[i] = iso
+				}
+			}
+			return strings.Join(names, ", ")
+		}},
+	}
+
+	//	`CsvWriter` columns for featureCodes_en.txt
+	CsvFeatureFields = []CsvField[geonames_parse.FeatureRec]{
+		{"code", true, func(r *geonames_parse.FeatureRec, _ *Names) string { return r.Code }},
+		{"class", false, func(r *geonames_parse.FeatureRec, _ *Names) string { return firstSegment(r.Code) }},
+		{"class_name", true, func(r *geonames_parse.FeatureRec, _ *Names) string { return FeatureClassNames[firstSegment(r.Code)] }},
+		{"name", true, func(r *geonames_parse.FeatureRec, _ *Names) string { return r.Name }},
+		{"description", true, func(r *geonames_parse.FeatureRec, _ *Names) string { return r.Desc }},
+	}
+
+	//	`CsvWriter` columns for hierarchy.txt
+	CsvHierarchyFields = []CsvField[geonames_parse.HierarchyRec]{
+		{"parent_id", true, func(r *geonames_parse.HierarchyRec, _ *Names) string { return itoa(r.ParentId) }},
+		{"child_id", true, func(r *geonames_parse.HierarchyRec, _ *Names) string { return itoa(r.ChildId) }},
+		{"type", true, func(r *geonames_parse.HierarchyRec, _ *Names) string { return r.Type }},
+	}
+
+	//	`CsvWriter` columns for iso-languagecodes.txt
+	CsvLanguageFields = []CsvField[geonames_parse.LanguageRec]{
+		{"iso_639_3", true, func(r *geonames_parse.LanguageRec, _ *Names) string { return r.Iso_639_3 }},
+		{"iso_639_2", true, func(r *geonames_parse.LanguageRec, _ *Names) string { return r.Iso_639_2 }},
+		{"iso_639_1", true, func(r *geonames_parse.LanguageRec, _ *Names) string { return r.Iso_639_1 }},
+		{"name", true, func(r *geonames_parse.LanguageRec, _ *Names) string { return r.Name }},
+	}
+
+	//	`CsvWriter` columns for allCountries.txt, with resolved `feature`, `country`, `admin1` and `admin2` names
+	CsvPlaceFields = []CsvField[geonames_parse.PlaceRec]{
+		{"geonameid", true, func(r *geonames_parse.PlaceRec, _ *Names) string { return itoa(r.Id) }},
+		{"name", true, func(r *geonames_parse.PlaceRec, _ *Names) string { return r.Name }},
+		{"asciiname", false, func(r *geonames_parse.PlaceRec, _ *Names) string { return r.NameAscii }},
+		{"alternatenames", false, func(r *geonames_parse.PlaceRec, _ *Names) string { return strings.Join(r.NamesAlt, ",") }},
+		{"latitude", true, func(r *geonames_parse.PlaceRec, _ *Names) string { return coord(r.LonLat, 1) }},
+		{"longitude", true, func(r *geonames_parse.PlaceRec, _ *Names) string { return coord(r.LonLat, 0) }},
+		{"feature_class", false, func(r *geonames_parse.PlaceRec, _ *Names) string { return r.Feature.Class }},
+		{"feature_class_name", false, func(r *geonames_parse.PlaceRec, _ *Names) string { return FeatureClassNames[r.Feature.Class] }},
+		{"feature_code", false, func(r *geonames_parse.PlaceRec, _ *Names) string { return r.Feature.Code }},
+		{"feature", true, func(r *geonames_parse.PlaceRec, n *Names) string {
+			return n.Feature(r.Feature.Class + "." + r.Feature.Code)
+		}},
+		{"country_code", false, func(r *geonames_parse.PlaceRec, _ *Names) string { return r.Country.Code }},
+		{"country", true, func(r *geonames_parse.PlaceRec, n *Names) string { return n.Country(r.Country.Code) }},
+		{"cc2", false, func(r *geonames_parse.PlaceRec, _ *Names) string { return strings.Join(r.Country.CodesAlt, ",") }},
+		{"admin1_code", false, func(r *geonames_parse.PlaceRec, _ *Names) string { return r.Admin.Code1 }},
+		{"admin1", true, func(r *geonames_parse.PlaceRec, n *Names) string {
+			return n.Admin(adminCode(r.Country.Code, r.Admin.Code1))
+		}},
+		{"admin2_code", false, func(r *geonames_parse.PlaceRec, _ *Names) string { return r.Admin.Code2 }},
+		{"admin2", true, func(r *geonames_parse.PlaceRec, n *Names) string {
+			return n.Admin(adminCode(r.Country.Code, r.Admin.Code1, r.Admin.Code2))
+		}},
+		{"admin3_code", false, func(r *geonames_parse.PlaceRec, _ *Names) string { return r.Admin.Code3 }},
+		{"admin4_code", false, func(r *geonames_parse.PlaceRec, _ *Names) string { return r.Admin.Code4 }},
+		{"population", true, func(r *geonames_parse.PlaceRec, _ *Names) string { return itoa(r.Population) }},
+		{"elevation", true, func(r *geonames_parse.PlaceRec, _ *Names) string { return itoa(r.Elevation) }},
+		{"timezone", true, func(r *geonames_parse.PlaceRec, _ *Names) string { return r.TimezoneName }},
+		{"modification_date", false, func(r *geonames_parse.PlaceRec, _ *Names) string { return date(r.Modified) }},
+	}
+
+	//	`CsvWriter` columns for zip_allCountries.txt
+	CsvPostalFields = []CsvField[geonames_parse.PostalRec]{
+		{"country_code", false, func(r *geonames_parse.PostalRec, _ *Names) string { return r.CountryCode }},
+		{"country", true, func(r *geonames_parse.PostalRec, n *Names) string { return n.Country(r.CountryCode) }},
+		{"postal_code", true, func(r *geonames_parse.PostalRec, _ *Names) string { return r.PostalCode }},
+		{"place_name", true, func(r *geonames_parse.PostalRec, _ *Names) string { return r.PlaceName }},
+		{"admin_name1", true, func(r *geonames_parse.PostalRec, _ *Names) string { return r.Admin.Name1 }},
+		{"admin_code1", false, func(r *geonames_parse.PostalRec, _ *Names) string { return r.Admin.Code1 }},
+		{"admin_name2", true, func(r *geonames_parse.PostalRec, _ *Names) string { return r.Admin.Name2 }},
+		{"admin_code2", false, func(r *geonames_parse.PostalRec, _ *Names) string { return r.Admin.Code2 }},
+		{"admin_name3", true, func(r *geonames_parse.PostalRec, _ *Names) string { return r.Admin.Name3 }},
+		{"admin_code3", false, func(r *geonames_parse.PostalRec, _ *Names) string { return r.Admin.Code3 }},
+		{"latitude", true, func(r *geonames_parse.PostalRec, _ *Names) string { return coord(r.LonLat, 1) }},
+		{"longitude", true, func(r *geonames_parse.PostalRec, _ *Names) string { return coord(r.LonLat, 0) }},
+		{"accuracy", false, func(r *geonames_parse.PostalRec, _ *Names) string {
+			if r.Accuracy == 0 {
+				return ""
+			}
+			return itoa(r.Accuracy)
+		}},
+	}
+
+	//	`CsvWriter` columns for timeZones.txt
+	CsvTimezoneFields = []CsvField[geonames_parse.TimezoneRec]{
+		{"country_code", false, func(r *geonames_parse.TimezoneRec, _ *Names) string { return r.CountryCode }},
+		{"country", true, func(r *geonames_parse.TimezoneRec, n *Names) string { return n.Country(r.CountryCode) }},
+		{"timezone", true, func(r *geonames_parse.TimezoneRec, _ *Names) string { return r.TimezoneName }},
+		{"gmt_offset", true, func(r *geonames_parse.TimezoneRec, _ *Names) string { return ftoa(r.OffsetGmt) }},
+		{"dst_offset", true, func(r *geonames_parse.TimezoneRec, _ *Names) string { return ftoa(r.OffsetDst) }},
+		{"raw_offset", true, func(r *geonames_parse.TimezoneRec, _ *Names) string { return ftoa(r.OffsetRaw) }},
+	}
+)
+
+//	One available column of a `CsvWriter` for records of type `T`.
+type CsvField[T any] struct {
+	//	Name used to select the column, and its default header
+	Name string
+
+	//	Whether the column is written if no columns are selected
+	Default bool
+
+	//	Returns the cell text for `r`. `names` may be `nil`, in which case resolved names are empty.
+	Value func(r *T, names *Names) string
+}
+
+//	Selects a `CsvField` by `Field` name, and optionally renames its `Header`.
+type CsvColumn struct {
+	Field  string
+	Header string
+}
+
+//	Parses a comma-separated column selection such as `name,country,population=Einwohner` into `CsvColumn`s,
+//	where a `=` suffix renames the column header.
+func ParseCsvColumns(spec string) (cols []CsvColumn) {
+	for _, s := range strings.Split(spec, ",") {
+		if s = strings.TrimSpace(s); len(s) > 0 {
+			field, header, _ := strings.Cut(s, "=")
+			cols = append(cols, CsvColumn{Field: strings.TrimSpace(field), Header: strings.TrimSpace(header)})
+		}
+	}
+	return
+}
+
+//	Resolves the codes in records to human-readable names for `CsvField`s, as read from the (small) countries,
+//	admin-divisions and feature-codes files.
+type Names struct {
+	//	Maps ISO-3166 alpha-2 codes to country names
+	Countries map[string]string
+
+	//	Maps full admin codes (eg. `US.CA` or `US.CA.037`) to admin division names
+	Admins map[string]string
+
+	//	Maps full feature codes (eg. `P.PPLC`) to feature names
+	Features map[string]string
+}
+
+//	Reads all `Names` from `geo`. Files that do not exist in `geo.DirPath` are ignored, so their names resolve to empty.
+func LoadNames(geo *geonames_parse.Iterator) (me *Names, err error) {
+	me = &Names{Countries: map[string]string{}, Admins: map[string]string{}, Features: map[string]string{}}
+	if err = ignoreNotExist(geo.Countries(func(_ int, r *geonames_parse.CountryRec) {
+		me.Countries[r.Code.Iso2] = r.Name
+	})); err == nil {
+		onAdmin := func(_ int, r *geonames_parse.AdminRec) { me.Admins[r.Code] = r.Name }
+		if err = ignoreNotExist(geo.Admin1(onAdmin)); err == nil {
+			if err = ignoreNotExist(geo.Admin2(onAdmin)); err == nil {
+				err = ignoreNotExist(geo.Features(func(_ int, r *geonames_parse.FeatureRec) {
+					me.Features[r.Code] = r.Name
+				}))
+			}
+		}
+	}
+	if err != nil {
+		me = nil
+	}
+	return
+}
+
+//	Returns the name of the country with the specified ISO-3166 alpha-2 `code`, or `""`.
+func (me *Names) Country(code string) string {
+	if me == nil {
+		return ""
+	}
+	return me.Countries[code]
+}
+
+//	Returns the name of the admin division with the specified full `code` (eg. `US.CA`), or `""`.
+func (me *Names) Admin(code string) string {
+	if me == nil || len(code) == 0 {
+		return ""
+	}
+	return me.Admins[code]
+}
+
+//	Returns the name of the feature with the specified full `code` (eg. `P.PPLC`), or `""`.
+func (me *Names) Feature(code string) string {
+	if me == nil {
+		return ""
+	}
+	return me.Features[code]
+}
+
+//	Writes records of type `T` as RFC 4180 CSV (with a header row and CRLF line breaks), one row per `Write`.
+//	`Close` must be called to flush all buffered output.
+type CsvWriter[T any] struct {
+	//	Number of rows written so far, excluding the header
+	Count int
+
+	w      *csv.Writer
+	names  *Names
+	values []func(*T, *Names) string
+	row    []string
+}
+
+//	Initializes a new `CsvWriter` writing the `columns` (or if none, all `Default` ones) of `fields` to `w`, and writes the header row.
+//	`names` may be `nil` if no resolved-name columns are needed.
+func NewCsvWriter[T any](w io.Writer, fields []CsvField[T], columns []CsvColumn, names *Names) (me *CsvWriter[T], err error) {
+	if len(columns) == 0 {
+		for _, f := range fields {
+			if f.Default {
+				columns = append(columns, CsvColumn{Field: f.Name})
+			}
+		}
+	}
+	me = &CsvWriter[T]{w: csv.NewWriter(w), names: names, row: make([]string, len(columns))}
+	me.w.UseCRLF = true
+	for i, col := range columns {
+		var value func(*T, *Names) string
+		for _, f := range fields {
+			if f.Name == col.Field {
+				value = f.Value
+				break
+			}
+		}
+		if value == nil {
+			avail := make([]string, len(fields))
+			for j := range fields {
+				avail[j] = fields[j].Name
+			}
+			return nil, fmt.Errorf("unknown CSV column %#v, expected one of: %s", col.Field, strings.Join(avail, ", "))
+		}
+		if me.values, me.row[i] = append(me.values, value), col.Header; len(col.Header) == 0 {
+			me.row[i] = col.Field
+		}
+	}
+	if err = me.w.Write(me.row); err != nil {
+		me = nil
+	}
+	return
+}
+
+//	Writes one row for `r`.
+func (me *CsvWriter[T]) Write(r *T) (err error) {
+	for i, value := range me.values {
+		me.row[i] = value(r, me.names)
+	}
+	if err = me.w.Write(me.row); err == nil {
+		me.Count++
+	}
+	return
+}
+
+//	Flushes all buffered output. Does not close the underlying `io.Writer`.
+func (me *CsvWriter[T]) Close() error {
+	me.w.Flush()
+	return me.w.Error()
+}
+
+//	Streams all records of the specified kind (`places`, `postals`, `admins`, `countries`, `features`, `timezones`, `hierarchy`,
+//	`languages` or `alternate_names`) from `geo` to `w` as CSV, returning the number of rows written.
+//
+//	`filter` (which may be `nil`) restricts places and postal codes, and its `Countries` also restrict admin divisions, countries and time zones.
+//	`names` may be `nil` if no resolved-name columns are selected.
+func Csv(geo *geonames_parse.Iterator, records string, w io.Writer, columns []CsvColumn, names *Names, filter *Filter) (count int, err error) {
+	switch records {
+	case "places":
+		return csvRecords(w, geo.Places, CsvPlaceFields, columns, names, filter.place)
+	case "postals":
+		return csvRecords(w, geo.PostalCodes, CsvPostalFields, columns, names, filter.postal)
+	case "admins":
+		return csvRecords(w, geo.AdminAll, CsvAdminFields, columns, names, func(r *geonames_parse.AdminRec) bool {
+			return filter == nil || filter.country(firstSegment(r.Code))
+		})
+	case "countries":
+		return csvRecords(w, geo.Countries, CsvCountryFields, columns, names, func(r *geonames_parse.CountryRec) bool {
+			return filter == nil || filter.country(r.Code.Iso2)
+		})
+	case "timezones":
+		return csvRecords(w, geo.Timezones, CsvTimezoneFields, columns, names, func(r *geonames_parse.TimezoneRec) bool {
+			return filter == nil || filter.country(r.CountryCode)
+		})
+	case "features":
+		return csvRecords(w, geo.Features, CsvFeatureFields, columns, names, nil)
+	case "hierarchy":
+		return csvRecords(w, geo.Hierarchy, CsvHierarchyFields, columns, names, nil)
+	case "languages":
+		return csvRecords(w, geo.Languages, CsvLanguageFields, columns, names, nil)
+	case "alternate_names":
+		return csvRecords(w, geo.AlternateNames, CsvAlternateNameFields, columns, names, nil)
+	}
+	return 0, fmt.Errorf("unknown CSV records %#v", records)
+}
+
+//	Writes all records of `iterate` accepted by `accept` (if not `nil`) through a new `CsvWriter`.
+func csvRecords[T any](w io.Writer, iterate func(func(int, *T)) error, fields []CsvField[T], columns []CsvColumn, names *Names, accept func(*T) bool) (count int, err error) {
+	var (
+		cw   *CsvWriter[T]
+		werr error
+	)
+	if cw, err = NewCsvWriter(w, fields, columns, names); err != nil {
+		return
+	}
+	if err = iterate(func(_ int, r *T) {
+		if werr == nil && (accept == nil || accept(r)) {
+			werr = cw.Write(r)
+		}
+	}); err == nil {
+		if err = werr; err == nil {
+			err = cw.Close()
+		}
+	}
+	return cw.Count, err
+}
+
+func adminCode(country string, codes ...string) string {
+	for _, c := range codes {
+		if len(c) == 0 || c == "00" {
+			return ""
+		}
+	}
+	return country + "." + strings.Join(codes, ".")
+}
+
+func firstSegment(code string) string {
+	country, _, _ := strings.Cut(code, ".")
+	return country
+}
+
+func coord(lonLat []float64, i int) string {
+	if len(lonLat) != 2 {
+		return ""
+	}
+	return ftoa(lonLat[i])
+}
+
+func flag(b bool) string {
+	if b {
+		return "1"
+	}
+	return ""
+}
+
+func ftoa(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+
+func itoa(i int64) string { return strconv.FormatInt(i, 10) }
+
+func ignoreNotExist(err error) error {
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
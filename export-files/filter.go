@@ -0,0 +1,74 @@
+//	Streams parsed `download.geonames.org/export/dump` records (via `parse-dumps` package) into file formats for maps, analytics and spreadsheets,
+//	without holding them in memory.
+package geonames_export
+
+import (
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+//	Restricts the records exported. All non-zero conditions must be met, those not applicable to a record type are ignored.
+type Filter struct {
+	//	Acceptable ISO-3166 alpha-2 country codes
+	Countries []string
+
+	//	Acceptable feature classes, eg. `"P"` for populated places or `"PA"` for those and administrative divisions
+	FeatureClasses string
+
+	//	Acceptable feature codes (eg. `PPLC`, `PPLA`)
+	FeatureCodes []string
+
+	//	Minimum population
+	MinPopulation int64
+
+	//	Bounding box as `[minLon, minLat, maxLon, maxLat]`
+	BBox []float64
+}
+
+func (me *Filter) place(r *geonames_parse.PlaceRec) bool {
+	if me == nil {
+		return true
+	}
+	if r.Population < me.MinPopulation {
+		return false
+	}
+	if len(me.FeatureClasses) > 0 && (len(r.Feature.Class) != 1 || !has(me.FeatureClasses, r.Feature.Class[0])) {
+		return false
+	}
+	if len(me.FeatureCodes) > 0 && !in(me.FeatureCodes, r.Feature.Code) {
+		return false
+	}
+	return me.country(r.Country.Code) && me.inBox(r.LonLat)
+}
+
+func (me *Filter) postal(r *geonames_parse.PostalRec) bool {
+	return me == nil || (me.country(r.CountryCode) && me.inBox(r.LonLat))
+}
+
+func (me *Filter) country(code string) bool {
+	return len(me.Countries) == 0 || in(me.Countries, code)
+}
+
+func (me *Filter) inBox(lonLat []float64) bool {
+	if len(me.BBox) != 4 {
+		return true
+	}
+	return len(lonLat) == 2 && lonLat[0] >= me.BBox[0] && lonLat[1] >= me.BBox[1] && lonLat[0] <= me.BBox[2] && lonLat[1] <= me.BBox[3]
+}
+
+func has(chars string, c byte) bool {
+	for i := 0; i < len(chars); i++ {
+		if chars[i] == c {
+			return true
+		}
+	}
+	return false
+}
+
+func in(vals []string, s string) bool {
+	for _, v := range vals {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
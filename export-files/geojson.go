@@ -0,0 +1,163 @@
+package geonames_export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+//	GeoJSON `properties` of a `PlaceRec` feature.
+type PlaceProperties struct {
+	Name          string   `json:"name"`
+	NameAscii     string   `json:"asciiname,omitempty"`
+	NamesAlt      []string `json:"alternatenames,omitempty"`
+	FeatureClass  string   `json:"feature_class"`
+	FeatureCode   string   `json:"feature_code"`
+	CountryCode   string   `json:"country_code"`
+	CountryCodes2 []string `json:"cc2,omitempty"`
+	Admin1Code    string   `json:"admin1_code,omitempty"`
+	Admin2Code    string   `json:"admin2_code,omitempty"`
+	Admin3Code    string   `json:"admin3_code,omitempty"`
+	Admin4Code    string   `json:"admin4_code,omitempty"`
+	Population    int64    `json:"population"`
+	Elevation     int64    `json:"elevation,omitempty"`
+	Timezone      string   `json:"timezone,omitempty"`
+	Modified      string   `json:"modification_date,omitempty"`
+}
+
+//	GeoJSON `properties` of a `PostalRec` feature.
+type PostalProperties struct {
+	CountryCode string `json:"country_code"`
+	PostalCode  string `json:"postal_code"`
+	PlaceName   string `json:"place_name"`
+	Admin1Name  string `json:"admin_name1,omitempty"`
+	Admin1Code  string `json:"admin_code1,omitempty"`
+	Admin2Name  string `json:"admin_name2,omitempty"`
+	Admin2Code  string `json:"admin_code2,omitempty"`
+	Admin3Name  string `json:"admin_name3,omitempty"`
+	Admin3Code  string `json:"admin_code3,omitempty"`
+	Accuracy    int64  `json:"accuracy,omitempty"`
+}
+
+type geoFeature struct {
+	Type       string      `json:"type"`
+	Id         interface{} `json:"id,omitempty"`
+	Geometry   geoPoint    `json:"geometry"`
+	Properties interface{} `json:"properties"`
+}
+
+type geoPoint struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+//	Writes GeoJSON point features, either as one `FeatureCollection` or as newline-delimited features (NDJSON / GeoJSONSeq).
+//	Records without coordinates are skipped. `Close` must be called to complete the output.
+type GeoJSONWriter struct {
+	//	Whether to write one feature per line instead of a `FeatureCollection`
+	Lines bool
+
+	//	Number of features written so far
+	Count int
+
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+//	Initializes a new `GeoJSONWriter` writing to `w`.
+func NewGeoJSONWriter(w io.Writer, lines bool) (me *GeoJSONWriter) {
+	me = &GeoJSONWriter{Lines: lines, w: bufio.NewWriterSize(w, 1<<16)}
+	me.enc = json.NewEncoder(me.w)
+	me.enc.SetEscapeHTML(false)
+	return
+}
+
+//	Writes `r` as a feature with `PlaceProperties`.
+func (me *GeoJSONWriter) WritePlace(r *geonames_parse.PlaceRec) error {
+	return me.write(r.Id, r.LonLat, &PlaceProperties{Name: r.Name, NameAscii: r.NameAscii, NamesAlt: r.NamesAlt, FeatureClass: r.Feature.Class,
+		FeatureCode: r.Feature.Code, CountryCode: r.Country.Code, CountryCodes2: r.Country.CodesAlt, Admin1Code: r.Admin.Code1, Admin2Code: r.Admin.Code2,
+		Admin3Code: r.Admin.Code3, Admin4Code: r.Admin.Code4, Population: r.Population, Elevation: r.Elevation, Timezone: r.TimezoneName, Modified: date(r.Modified)})
+}
+
+func date(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+//	Writes `r` as a feature with `PostalProperties`.
+func (me *GeoJSONWriter) WritePostal(r *geonames_parse.PostalRec) error {
+	return me.write(nil, r.LonLat, &PostalProperties{CountryCode: r.CountryCode, PostalCode: r.PostalCode, PlaceName: r.PlaceName,
+		Admin1Name: r.Admin.Name1, Admin1Code: r.Admin.Code1, Admin2Name: r.Admin.Name2, Admin2Code: r.Admin.Code2,
+		Admin3Name: r.Admin.Name3, Admin3Code: r.Admin.Code3, Accuracy: r.Accuracy})
+}
+
+func (me *GeoJSONWriter) write(id interface{}, lonLat []float64, props interface{}) (err error) {
+	if len(lonLat) != 2 {
+		return
+	}
+	if !me.Lines {
+		sep := ",\n"
+		if me.Count == 0 {
+			sep = `{"type":"FeatureCollection","features":[` + "\n"
+		}
+		if _, err = me.w.WriteString(sep); err != nil {
+			return
+		}
+	}
+	if err = me.enc.Encode(&geoFeature{Type: "Feature", Id: id, Geometry: geoPoint{Type: "Point", Coordinates: lonLat}, Properties: props}); err == nil {
+		me.Count++
+	}
+	return
+}
+
+//	Completes the `FeatureCollection` (if not `Lines`) and flushes all buffered output. Does not close the underlying `io.Writer`.
+func (me *GeoJSONWriter) Close() (err error) {
+	if !me.Lines {
+		if me.Count == 0 {
+			_, err = me.w.WriteString(`{"type":"FeatureCollection","features":[]}` + "\n")
+		} else {
+			_, err = me.w.WriteString("]}\n")
+		}
+	}
+	if err == nil {
+		err = me.w.Flush()
+	}
+	return
+}
+
+//	Streams all places of `geo` accepted by `filter` (which may be `nil`) to `w` as GeoJSON, returning the number of features written.
+func PlacesGeoJSON(geo *geonames_parse.Iterator, w io.Writer, lines bool, filter *Filter) (count int, err error) {
+	var werr error
+	gw := NewGeoJSONWriter(w, lines)
+	if err = geo.Places(func(_ int, r *geonames_parse.PlaceRec) {
+		if werr == nil && filter.place(r) {
+			werr = gw.WritePlace(r)
+		}
+	}); err == nil {
+		if err = werr; err == nil {
+			err = gw.Close()
+		}
+	}
+	return gw.Count, err
+}
+
+//	Streams all postal codes of `geo` accepted by `filter` (which may be `nil`) to `w` as GeoJSON, returning the number of features written.
+func PostalsGeoJSON(geo *geonames_parse.Iterator, w io.Writer, lines bool, filter *Filter) (count int, err error) {
+	var werr error
+	gw := NewGeoJSONWriter(w, lines)
+	if err = geo.PostalCodes(func(_ int, r *geonames_parse.PostalRec) {
+		if werr == nil && filter.postal(r) {
+			werr = gw.WritePostal(r)
+		}
+	}); err == nil {
+		if err = werr; err == nil {
+			err = gw.Close()
+		}
+	}
+	return gw.Count, err
+}
@@ -0,0 +1,331 @@
+package geonames_export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+//	Parquet row of a `PlaceRec`.
+type PlaceRow struct {
+	Id            int64     `parquet:"id"`
+	Name          string    `parquet:"name"`
+	NameAscii     string    `parquet:"asciiname,optional"`
+	NamesAlt      []string  `parquet:"alternatenames,list"`
+	Lon           *float64  `parquet:"lon,optional"`
+	Lat           *float64  `parquet:"lat,optional"`
+	FeatureClass  string    `parquet:"feature_class,dict"`
+	FeatureCode   string    `parquet:"feature_code,dict"`
+	CountryCode   string    `parquet:"country_code,dict"`
+	CountryCodes2 []string  `parquet:"cc2,list"`
+	Admin1Code    string    `parquet:"admin1_code,optional"`
+	Admin2Code    string    `parquet:"admin2_code,optional"`
+	Admin3Code    string    `parquet:"admin3_code,optional"`
+	Admin4Code    string    `parquet:"admin4_code,optional"`
+	Population    int64     `parquet:"population"`
+	Elevation     int64     `parquet:"elevation"`
+	Timezone      string    `parquet:"timezone,dict,optional"`
+	Modified      time.Time `parquet:"modification_date,optional,timestamp(millisecond)"`
+}
+
+//	Parquet row of a `PostalRec`.
+type PostalRow struct {
+	CountryCode string   `parquet:"country_code,dict"`
+	PostalCode  string   `parquet:"postal_code"`
+	PlaceName   string   `parquet:"place_name"`
+	Admin1Name  string   `parquet:"admin_name1,optional"`
+	Admin1Code  string   `parquet:"admin_code1,optional"`
+	Admin2Name  string   `parquet:"admin_name2,optional"`
+	Admin2Code  string   `parquet:"admin_code2,optional"`
+	Admin3Name  string   `parquet:"admin_name3,optional"`
+	Admin3Code  string   `parquet:"admin_code3,optional"`
+	Lon         *float64 `parquet:"lon,optional"`
+	Lat         *float64 `parquet:"lat,optional"`
+	Accuracy    int64    `parquet:"accuracy"`
+}
+
+//	Parquet row of an `AdminRec`.
+type AdminRow struct {
+	Code        string `parquet:"code"`
+	CountryCode string `parquet:"country_code,dict"`
+	Level       int32  `parquet:"level"`
+	Name        string `parquet:"name"`
+	NameAscii   string `parquet:"asciiname,optional"`
+	Id          int64  `parquet:"geonameid"`
+}
+
+//	Parquet row of a `CountryRec`.
+type CountryRow struct {
+	Iso2         string   `parquet:"iso"`
+	Iso3         string   `parquet:"iso3"`
+	IsoNumeric   string   `parquet:"iso_numeric"`
+	Fips         string   `parquet:"fips,optional"`
+	Name         string   `parquet:"country"`
+	Capital      string   `parquet:"capital,optional"`
+	AreaSqKm     int64    `parquet:"area_sq_km"`
+	Population   int64    `parquet:"population"`
+	Continent    string   `parquet:"continent,dict"`
+	Tld          string   `parquet:"tld,optional"`
+	CurrencyCode string   `parquet:"currency_code,optional"`
+	CurrencyName string   `parquet:"currency_name,optional"`
+	CallingCode  string   `parquet:"phone,optional"`
+	PostalFormat string   `parquet:"postal_code_format,optional"`
+	PostalRegex  string   `parquet:"postal_code_regex,optional"`
+	Languages    []string `parquet:"languages,list"`
+	Id           int64    `parquet:"geonameid"`
+	Neighbors    []string `parquet:"neighbours,list"`
+}
+
+//	Parquet row of an `AlternateNameRec`.
+type AlternateNameRow struct {
+	Id         int64  `parquet:"alternatenameid"`
+	PlaceId    int64  `parquet:"geonameid"`
+	Language   string `parquet:"isolanguage,dict,optional"`
+	Name       string `parquet:"alternate_name"`
+	Preferred  bool   `parquet:"is_preferred_name"`
+	Short      bool   `parquet:"is_short_name"`
+	Colloquial bool   `parquet:"is_colloquial"`
+	Historic   bool   `parquet:"is_historic"`
+}
+
+//	Controls `Parquet`.
+type ParquetOptions struct {
+	//	Output directory, receiving one sub-directory per table (`places`, `postals`, `admins`, `countries`, `alternate_names`)
+	DirPath string
+
+	//	Whether to split the `places`, `postals` and `admins` tables into Hive-style `country_code=XX` sub-directories
+	//	(recognized as partitions by DuckDB, Spark and others), whose files then leave out the `country_code` column.
+	//	All partitions of a table stay open while it is written, sharing one row group's worth of buffered rows.
+	PartitionByCountry bool
+
+	//	Whether to also export `alternate_names` (from the large `alternateNames.txt`, if present)
+	AlternateNames bool
+
+	//	Restricts `places` and `postals`, may be `nil`
+	Filter *Filter
+
+	//	Rows per row group, defaults to `128 * 1024` (and partitioned tables flush the fullest partition's row group early
+	//	whenever all their partitions together buffer this many rows)
+	RowGroupSize int
+}
+
+//	Exports the places, postal codes, admin divisions, countries and (optionally) alternate names of `geo` as Parquet files,
+//	streaming each record straight through. Returns the number of rows written per table.
+func Parquet(geo *geonames_parse.Iterator, opts *ParquetOptions) (counts map[string]int, err error) {
+	counts = map[string]int{}
+	if err = os.MkdirAll(opts.DirPath, 0755); err != nil {
+		return
+	}
+	countries := newParquetTable[CountryRow](opts, "countries", false)
+	if err = exportTable(countries, geo.Countries, func(r *geonames_parse.CountryRec) (string, *CountryRow) {
+		return "", &CountryRow{Iso2: r.Code.Iso2, Iso3: r.Code.Iso3, IsoNumeric: r.Code.IsoNum, Fips: r.Code.Fips, Name: r.Name, Capital: r.Capital,
+			AreaSqKm: r.AreaSqKm, Population: r.Population, Continent: r.Continent, Tld: r.Tld, CurrencyCode: r.Currency.Code, CurrencyName: r.Currency.Name,
+			CallingCode: r.CallingCode, PostalFormat: r.PostalCode.Format, PostalRegex: r.PostalCode.Regex, Languages: r.Languages, Id: r.Id, Neighbors: r.Neighbors}
+	}); err != nil {
+		return
+	}
+	counts[countries.name] = countries.count
+
+	admins := newParquetTable[AdminRow](opts, "admins", opts.PartitionByCountry)
+	if err = exportTable(admins, geo.AdminAll, func(r *geonames_parse.AdminRec) (string, *AdminRow) {
+		country, _, _ := strings.Cut(r.Code, ".")
+		return country, &AdminRow{Code: r.Code, CountryCode: country, Level: int32(strings.Count(r.Code, ".")), Name: r.Name, NameAscii: r.NameAscii, Id: r.Id}
+	}); err != nil {
+		return
+	}
+	counts[admins.name] = admins.count
+
+	postals := newParquetTable[PostalRow](opts, "postals", opts.PartitionByCountry)
+	if err = exportTable(postals, geo.PostalCodes, func(r *geonames_parse.PostalRec) (string, *PostalRow) {
+		if !opts.Filter.postal(r) {
+			return "", nil
+		}
+		row := &PostalRow{CountryCode: r.CountryCode, PostalCode: r.PostalCode, PlaceName: r.PlaceName, Admin1Name: r.Admin.Name1, Admin1Code: r.Admin.Code1,
+			Admin2Name: r.Admin.Name2, Admin2Code: r.Admin.Code2, Admin3Name: r.Admin.Name3, Admin3Code: r.Admin.Code3, Accuracy: r.Accuracy}
+		row.Lon, row.Lat = lonLatPtrs(r.LonLat)
+		return r.CountryCode, row
+	}); err != nil {
+		return
+	}
+	counts[postals.name] = postals.count
+
+	places := newParquetTable[PlaceRow](opts, "places", opts.PartitionByCountry)
+	if err = exportTable(places, geo.Places, func(r *geonames_parse.PlaceRec) (string, *PlaceRow) {
+		if !opts.Filter.place(r) {
+			return "", nil
+		}
+		row := &PlaceRow{Id: r.Id, Name: r.Name, NameAscii: r.NameAscii, NamesAlt: r.NamesAlt, FeatureClass: r.Feature.Class, FeatureCode: r.Feature.Code,
+			CountryCode: r.Country.Code, CountryCodes2: r.Country.CodesAlt, Admin1Code: r.Admin.Code1, Admin2Code: r.Admin.Code2, Admin3Code: r.Admin.Code3,
+			Admin4Code: r.Admin.Code4, Population: r.Population, Elevation: r.Elevation, Timezone: r.TimezoneName, Modified: r.Modified}
+		row.Lon, row.Lat = lonLatPtrs(r.LonLat)
+		return r.Country.Code, row
+	}); err != nil {
+		return
+	}
+	counts[places.name] = places.count
+
+	if opts.AlternateNames {
+		altNames := newParquetTable[AlternateNameRow](opts, "alternate_names", false)
+		if err = exportTable(altNames, geo.AlternateNames, func(r *geonames_parse.AlternateNameRec) (string, *AlternateNameRow) {
+			return "", &AlternateNameRow{Id: r.Id, PlaceId: r.PlaceId, Language: r.Language, Name: r.Name, Preferred: r.Preferred, Short: r.Short,
+				Colloquial: r.Colloquial, Historic: r.Historic}
+		}); err != nil {
+			return
+		}
+		counts[altNames.name] = altNames.count
+	}
+	return
+}
+
+func lonLatPtrs(lonLat []float64) (lon, lat *float64) {
+	if len(lonLat) == 2 {
+		lon, lat = &lonLat[0], &lonLat[1]
+	}
+	return
+}
+
+//	One exported table: a single Parquet file, or one per country partition.
+type parquetTable[T any] struct {
+	name         string
+	opts         *ParquetOptions
+	partitioned  bool
+	rowGroupSize int
+	count        int
+	pending      int
+	parts        map[string]*parquetPart[T]
+}
+
+type parquetPart[T any] struct {
+	file   *os.File
+	writer *parquet.GenericWriter[T]
+	rows   []T
+
+	//	Rows not yet in a completed row group
+	pending int
+}
+
+func newParquetTable[T any](opts *ParquetOptions, name string, partitioned bool) *parquetTable[T] {
+	rowGroupSize := opts.RowGroupSize
+	if rowGroupSize <= 0 {
+		rowGroupSize = 128 * 1024
+	}
+	return &parquetTable[T]{name: name, opts: opts, partitioned: partitioned, rowGroupSize: rowGroupSize, parts: map[string]*parquetPart[T]{}}
+}
+
+//	Feeds all records of `iterate` through `toRow` (which returns `nil` for records to be skipped) into `table`, then closes all its files.
+func exportTable[T, R any](table *parquetTable[T], iterate func(func(int, *R)) error, toRow func(*R) (string, *T)) (err error) {
+	var werr error
+	err = iterate(func(_ int, rec *R) {
+		if werr == nil {
+			if country, row := toRow(rec); row != nil {
+				werr = table.write(country, row)
+			}
+		}
+	})
+	if err == nil {
+		err = werr
+	}
+	if cerr := table.close(); err == nil {
+		err = cerr
+	}
+	return
+}
+
+func (me *parquetTable[T]) write(country string, row *T) (err error) {
+	var part *parquetPart[T]
+	if part, err = me.part(country); err == nil {
+		part.rows, part.pending = append(part.rows, *row), part.pending+1
+		me.count, me.pending = me.count+1, me.pending+1
+		if part.pending >= me.rowGroupSize {
+			err = me.flushRowGroup(part)
+		} else if len(part.rows) >= 1024 {
+			err = part.flush()
+		}
+		if err == nil && me.partitioned && me.pending >= me.rowGroupSize {
+			// too many rows buffered across all partitions: complete the fullest one's row group
+			var fullest *parquetPart[T]
+			for _, p := range me.parts {
+				if fullest == nil || p.pending > fullest.pending {
+					fullest = p
+				}
+			}
+			err = me.flushRowGroup(fullest)
+		}
+	}
+	return
+}
+
+//	Writes all pending rows of `part` as a completed row group.
+func (me *parquetTable[T]) flushRowGroup(part *parquetPart[T]) (err error) {
+	if err = part.flush(); err == nil {
+		err = part.writer.Flush()
+	}
+	me.pending, part.pending = me.pending-part.pending, 0
+	return
+}
+
+//	Returns the (newly created, if need be) partition for `country`, or the only one if not `me.partitioned`.
+func (me *parquetTable[T]) part(country string) (part *parquetPart[T], err error) {
+	if !me.partitioned {
+		country = ""
+	}
+	if part = me.parts[country]; part == nil {
+		dirPath := filepath.Join(me.opts.DirPath, me.name)
+		if len(country) > 0 {
+			dirPath = filepath.Join(dirPath, "country_code="+country)
+		} else if me.partitioned {
+			dirPath = filepath.Join(dirPath, "country_code=__HIVE_DEFAULT_PARTITION__")
+		}
+		part = &parquetPart[T]{}
+		if err = os.MkdirAll(dirPath, 0755); err == nil {
+			part.file, err = os.Create(filepath.Join(dirPath, "part-0.parquet"))
+		}
+		if err != nil {
+			return nil, err
+		}
+		options := []parquet.WriterOption{parquet.MaxRowsPerRowGroup(int64(me.rowGroupSize))}
+		if me.partitioned {
+			// the partition directory holds the country code
+			options = append(options, parquet.StructTag(`parquet:"-"`, "CountryCode"))
+		}
+		part.writer = parquet.NewGenericWriter[T](part.file, options...)
+		me.parts[country] = part
+	}
+	return
+}
+
+func (me *parquetPart[T]) flush() (err error) {
+	if len(me.rows) > 0 {
+		_, err = me.writer.Write(me.rows)
+		me.rows = me.rows[:0]
+	}
+	return
+}
+
+func (me *parquetTable[T]) close() (err error) {
+	if len(me.parts) == 0 && !me.partitioned {
+		// always produce the file, if empty, so that readers find the table
+		if _, err = me.part(""); err != nil {
+			return
+		}
+	}
+	for _, part := range me.parts {
+		e := part.flush()
+		if e == nil {
+			e = part.writer.Close()
+		}
+		if cerr := part.file.Close(); e == nil {
+			e = cerr
+		}
+		if err == nil {
+			err = e
+		}
+	}
+	me.parts, me.pending = map[string]*parquetPart[T]{}, 0
+	return
+}
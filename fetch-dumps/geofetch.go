@@ -2,14 +2,21 @@
 package geonames_fetch
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/metaleap/go-util/fs"
-	"github.com/metaleap/go-util/net"
 )
 
 var (
@@ -18,44 +25,86 @@ var (
 
 	//	Maps relative file URLs to local destination file names.
 	GeoFiles = map[string]string{
-		"admin1CodesASCII.txt": "dump/admin1CodesASCII.txt",
-		"admin2Codes.txt":      "dump/admin2Codes.txt",
-		"allCountries.txt":     "dump/allCountries.zip",
-		// "alternateNames.txt":    "dump/alternateNames.zip",
-		"countryInfo.txt":     "dump/countryInfo.txt",
-		"featureCodes_en.txt": "dump/featureCodes_en.txt",
-		"hierarchy.txt":       "dump/hierarchy.zip",
-		// "iso-languagecodes.txt": "dump/iso-languagecodes.txt",
-		"timeZones.txt":        "dump/timeZones.txt",
-		"zip_allCountries.txt": "zip/allCountries.zip",
+		"admin1CodesASCII.txt":  "dump/admin1CodesASCII.txt",
+		"admin2Codes.txt":       "dump/admin2Codes.txt",
+		"allCountries.txt":      "dump/allCountries.zip",
+		"alternateNames.txt":    "dump/alternateNames.zip",
+		"countryInfo.txt":       "dump/countryInfo.txt",
+		"featureCodes_en.txt":   "dump/featureCodes_en.txt",
+		"hierarchy.txt":         "dump/hierarchy.zip",
+		"iso-languagecodes.txt": "dump/iso-languagecodes.txt",
+		"timeZones.txt":         "dump/timeZones.txt",
+		"zip_allCountries.txt":  "zip/allCountries.zip",
 	}
+
+	//	Whether `FetchAllFiles` also fetches the `AlternateNameFiles` (`alternateNames.zip` alone is a download of several hundred MB)
+	FetchAlternateNames = false
+
+	//	The keys in `GeoFiles` that `FetchAllFiles` skips unless `FetchAlternateNames`
+	AlternateNameFiles = []string{"alternateNames.txt", "iso-languagecodes.txt"}
+
+	//	Name of the file in the output directory in which `FetchFile` records a `Fetched` entry per fetched file
+	FetchedFileName = "fetched.json"
+
+	fetchedLock sync.Mutex
 )
 
-//	Fetches all files in `GeoFiles` in parallel using `FetchFile`.
+//	Describes one download by `FetchFile`.
+type Fetched struct {
+	//	Full URL of the download
+	Url string `json:"url"`
+
+	//	Size in bytes of the local (extracted, if a ZIP archive) file
+	Size int64 `json:"size"`
+
+	//	The download's `Last-Modified` time as reported by the server, ie. the publication date of the dump file, or zero if not reported
+	LastModified time.Time `json:"lastModified"`
+
+	//	When the download finished
+	Time time.Time `json:"time"`
+}
+
+//	Fetches all files in `GeoFiles` (except the `AlternateNameFiles` unless `FetchAlternateNames`) in parallel using `FetchFile`.
 func FetchAllFiles(outDir string) (errs []error) {
-	var wait sync.WaitGroup
+	var (
+		wait sync.WaitGroup
+		mu   sync.Mutex
+	)
 	goFetch := func(fileName, relUrl string) {
 		defer wait.Done()
 		if err := FetchFile(outDir, fileName, relUrl); err != nil {
+			mu.Lock()
 			errs = append(errs, err)
+			mu.Unlock()
 		}
 	}
 	for fileName, relUrl := range GeoFiles {
-		wait.Add(1)
-		go goFetch(fileName, relUrl)
+		if FetchAlternateNames || !isAlternateNameFile(fileName) {
+			wait.Add(1)
+			go goFetch(fileName, relUrl)
+		}
 	}
 	wait.Wait()
 	return
 }
 
-//	Downloads the file at `BaseUrl + relUrl` to `outDir + fileName`.
+func isAlternateNameFile(fileName string) bool {
+	for _, name := range AlternateNameFiles {
+		if name == fileName {
+			return true
+		}
+	}
+	return false
+}
+
+//	Downloads the file at `BaseUrl + relUrl` to `outDir + fileName`, and records it in `outDir + FetchedFileName`.
 //
 //	If it is a ZIP archive file, it is extracted in place and deleted.
 func FetchFile(outDir, fileName, relUrl string) (err error) {
-	fullUrl := "http://download.geonames.org/export/" + relUrl
+	fullUrl := BaseUrl + relUrl
 	filePath := filepath.Join(outDir, strings.Replace(relUrl, "/", "_", -1))
-	err = unet.DownloadFile(fullUrl, filePath)
-	if err == nil {
+	var lastModified time.Time
+	if lastModified, err = download(fullUrl, filePath); err == nil {
 		if strings.HasSuffix(filePath, ".zip") {
 			log.Printf("UNZIP: %s from %s\n", fileName, filePath)
 			err = ufs.ExtractZipFile(filePath, outDir, true, "zip_", fileName)
@@ -63,5 +112,120 @@ func FetchFile(outDir, fileName, relUrl string) (err error) {
 			err = os.Rename(filePath, filepath.Join(outDir, fileName))
 		}
 	}
+	if err == nil {
+		var fi os.FileInfo
+		if fi, err = os.Stat(filepath.Join(outDir, fileName)); err == nil {
+			err = recordFetched(outDir, fileName, &Fetched{Url: fullUrl, Size: fi.Size(), LastModified: lastModified, Time: time.Now()})
+		}
+	}
+	return
+}
+
+func download(fullUrl, filePath string) (lastModified time.Time, err error) {
+	var (
+		resp *http.Response
+		file *os.File
+	)
+	if resp, err = http.Get(fullUrl); err == nil {
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return lastModified, fmt.Errorf("%s: %s", fullUrl, resp.Status)
+		}
+		lastModified, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+		if file, err = os.Create(filePath); err == nil {
+			if _, err = io.Copy(file, resp.Body); err == nil {
+				err = file.Close()
+			} else {
+				file.Close()
+			}
+		}
+	}
+	return
+}
+
+//	Returns the `Fetched` entries recorded in `outDir + FetchedFileName` by file name, or none if that file does not exist.
+func ReadFetched(outDir string) (fetched map[string]*Fetched, err error) {
+	var raw []byte
+	if raw, err = os.ReadFile(filepath.Join(outDir, FetchedFileName)); err == nil {
+		err = json.Unmarshal(raw, &fetched)
+	} else if os.IsNotExist(err) {
+		err = nil
+	}
+	return
+}
+
+func recordFetched(outDir, fileName string, f *Fetched) (err error) {
+	fetchedLock.Lock()
+	defer fetchedLock.Unlock()
+	var fetched map[string]*Fetched
+	if fetched, err = ReadFetched(outDir); err == nil {
+		if fetched == nil {
+			fetched = map[string]*Fetched{}
+		}
+		fetched[fileName] = f
+		var raw []byte
+		if raw, err = json.MarshalIndent(fetched, "", "\t"); err == nil {
+			err = os.WriteFile(filepath.Join(outDir, FetchedFileName), raw, 0644)
+		}
+	}
+	return
+}
+
+//	Describes a previously fetched file.
+type LocalFile struct {
+	//	File name, one of the keys in `GeoFiles`
+	Name string `bson:"name"`
+
+	//	File size in bytes
+	Size int64 `bson:"size"`
+
+	//	Last-modified time of the local file
+	ModTime time.Time `bson:"modTime"`
+
+	//	The server's `Fetched.LastModified` time of the file, or zero if it was not fetched via `FetchFile` or has changed since
+	LastModified time.Time `bson:"lastModified,omitempty"`
+
+	//	Hex-encoded SHA-256 of the file contents, if requested
+	Sha256 string `bson:"sha256,omitempty"`
+}
+
+//	Returns a `LocalFile` for each file in `GeoFiles` that exists in `outDir`, sorted by `Name`.
+//
+//	If `checksums` is `true`, each file is read in full to compute its `Sha256`.
+func LocalFiles(outDir string, checksums bool) (files []LocalFile, err error) {
+	var (
+		fi      os.FileInfo
+		file    *os.File
+		fetched map[string]*Fetched
+	)
+	if fetched, err = ReadFetched(outDir); err != nil {
+		return
+	}
+	for fileName := range GeoFiles {
+		if fi, err = os.Stat(filepath.Join(outDir, fileName)); err != nil {
+			if os.IsNotExist(err) {
+				err = nil
+				continue
+			}
+			return
+		}
+		lf := LocalFile{Name: fileName, Size: fi.Size(), ModTime: fi.ModTime()}
+		if f := fetched[fileName]; f != nil && f.Size == lf.Size {
+			lf.LastModified = f.LastModified
+		}
+		if checksums {
+			if file, err = os.Open(filepath.Join(outDir, fileName)); err != nil {
+				return
+			}
+			hash := sha256.New()
+			_, err = io.Copy(hash, file)
+			if file.Close(); err != nil {
+				return
+			}
+			lf.Sha256 = hex.EncodeToString(hash.Sum(nil))
+		}
+		files = append(files, lf)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
 	return
 }
@@ -0,0 +1,1639 @@
+// Lookups over parsed GeoNames dumps: nearest places (single and batched), name search,
+// postal codes, countries, administrative divisions and time zones.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: geonames.proto
+
+package geonames_grpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type LonLat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lon           float64                `protobuf:"fixed64,1,opt,name=lon,proto3" json:"lon,omitempty"`
+	Lat           float64                `protobuf:"fixed64,2,opt,name=lat,proto3" json:"lat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LonLat) Reset() {
+	*x = LonLat{}
+	mi := &file_geonames_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LonLat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LonLat) ProtoMessage() {}
+
+func (x *LonLat) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LonLat.ProtoReflect.Descriptor instead.
+func (*LonLat) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LonLat) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+func (x *LonLat) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+// Restricts nearest-place results. All non-empty conditions must be met.
+type PlaceFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Acceptable feature classes, eg. "P" or "PA".
+	FeatureClasses string `protobuf:"bytes,1,opt,name=feature_classes,json=featureClasses,proto3" json:"feature_classes,omitempty"`
+	// Acceptable feature codes, eg. "PPLC".
+	FeatureCodes []string `protobuf:"bytes,2,rep,name=feature_codes,json=featureCodes,proto3" json:"feature_codes,omitempty"`
+	// Acceptable ISO-3166 alpha-2 country codes.
+	CountryCodes  []string `protobuf:"bytes,3,rep,name=country_codes,json=countryCodes,proto3" json:"country_codes,omitempty"`
+	MinPopulation int64    `protobuf:"varint,4,opt,name=min_population,json=minPopulation,proto3" json:"min_population,omitempty"`
+	MaxDistanceKm float64  `protobuf:"fixed64,5,opt,name=max_distance_km,json=maxDistanceKm,proto3" json:"max_distance_km,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaceFilter) Reset() {
+	*x = PlaceFilter{}
+	mi := &file_geonames_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceFilter) ProtoMessage() {}
+
+func (x *PlaceFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceFilter.ProtoReflect.Descriptor instead.
+func (*PlaceFilter) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PlaceFilter) GetFeatureClasses() string {
+	if x != nil {
+		return x.FeatureClasses
+	}
+	return ""
+}
+
+func (x *PlaceFilter) GetFeatureCodes() []string {
+	if x != nil {
+		return x.FeatureCodes
+	}
+	return nil
+}
+
+func (x *PlaceFilter) GetCountryCodes() []string {
+	if x != nil {
+		return x.CountryCodes
+	}
+	return nil
+}
+
+func (x *PlaceFilter) GetMinPopulation() int64 {
+	if x != nil {
+		return x.MinPopulation
+	}
+	return 0
+}
+
+func (x *PlaceFilter) GetMaxDistanceKm() float64 {
+	if x != nil {
+		return x.MaxDistanceKm
+	}
+	return 0
+}
+
+type AdminDivision struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 1 for ADM1 through 4 for ADM4.
+	Level int32 `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"`
+	// Full dotted code including the country code, eg. "DE.02.091".
+	Code          string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Id            int64  `protobuf:"varint,3,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	NameAscii     string `protobuf:"bytes,5,opt,name=name_ascii,json=nameAscii,proto3" json:"name_ascii,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminDivision) Reset() {
+	*x = AdminDivision{}
+	mi := &file_geonames_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminDivision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminDivision) ProtoMessage() {}
+
+func (x *AdminDivision) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminDivision.ProtoReflect.Descriptor instead.
+func (*AdminDivision) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AdminDivision) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *AdminDivision) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *AdminDivision) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AdminDivision) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AdminDivision) GetNameAscii() string {
+	if x != nil {
+		return x.NameAscii
+	}
+	return ""
+}
+
+type Place struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name         string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	NameAscii    string                 `protobuf:"bytes,3,opt,name=name_ascii,json=nameAscii,proto3" json:"name_ascii,omitempty"`
+	Location     *LonLat                `protobuf:"bytes,4,opt,name=location,proto3" json:"location,omitempty"`
+	FeatureClass string                 `protobuf:"bytes,5,opt,name=feature_class,json=featureClass,proto3" json:"feature_class,omitempty"`
+	FeatureCode  string                 `protobuf:"bytes,6,opt,name=feature_code,json=featureCode,proto3" json:"feature_code,omitempty"`
+	CountryCode  string                 `protobuf:"bytes,7,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	// Known administrative divisions, most-general first.
+	Admins     []*AdminDivision `protobuf:"bytes,8,rep,name=admins,proto3" json:"admins,omitempty"`
+	Population int64            `protobuf:"varint,9,opt,name=population,proto3" json:"population,omitempty"`
+	Elevation  int64            `protobuf:"varint,10,opt,name=elevation,proto3" json:"elevation,omitempty"`
+	Timezone   string           `protobuf:"bytes,11,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	// Set by Nearest and NearestBatch.
+	DistanceKm float64 `protobuf:"fixed64,12,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	// Set by Search: relevance, higher is better.
+	Score float64 `protobuf:"fixed64,13,opt,name=score,proto3" json:"score,omitempty"`
+	// Set by Search: the place name that matched the query.
+	MatchedName   string `protobuf:"bytes,14,opt,name=matched_name,json=matchedName,proto3" json:"matched_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Place) Reset() {
+	*x = Place{}
+	mi := &file_geonames_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Place) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Place) ProtoMessage() {}
+
+func (x *Place) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Place.ProtoReflect.Descriptor instead.
+func (*Place) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Place) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Place) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Place) GetNameAscii() string {
+	if x != nil {
+		return x.NameAscii
+	}
+	return ""
+}
+
+func (x *Place) GetLocation() *LonLat {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *Place) GetFeatureClass() string {
+	if x != nil {
+		return x.FeatureClass
+	}
+	return ""
+}
+
+func (x *Place) GetFeatureCode() string {
+	if x != nil {
+		return x.FeatureCode
+	}
+	return ""
+}
+
+func (x *Place) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *Place) GetAdmins() []*AdminDivision {
+	if x != nil {
+		return x.Admins
+	}
+	return nil
+}
+
+func (x *Place) GetPopulation() int64 {
+	if x != nil {
+		return x.Population
+	}
+	return 0
+}
+
+func (x *Place) GetElevation() int64 {
+	if x != nil {
+		return x.Elevation
+	}
+	return 0
+}
+
+func (x *Place) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *Place) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+func (x *Place) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Place) GetMatchedName() string {
+	if x != nil {
+		return x.MatchedName
+	}
+	return ""
+}
+
+type PlacesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Places        []*Place               `protobuf:"bytes,1,rep,name=places,proto3" json:"places,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlacesResponse) Reset() {
+	*x = PlacesResponse{}
+	mi := &file_geonames_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlacesResponse) ProtoMessage() {}
+
+func (x *PlacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlacesResponse.ProtoReflect.Descriptor instead.
+func (*PlacesResponse) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PlacesResponse) GetPlaces() []*Place {
+	if x != nil {
+		return x.Places
+	}
+	return nil
+}
+
+type NearestRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Location *LonLat                `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	// Maximum number of places returned, defaults to 1.
+	Limit         int32        `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Filter        *PlaceFilter `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NearestRequest) Reset() {
+	*x = NearestRequest{}
+	mi := &file_geonames_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NearestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NearestRequest) ProtoMessage() {}
+
+func (x *NearestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NearestRequest.ProtoReflect.Descriptor instead.
+func (*NearestRequest) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *NearestRequest) GetLocation() *LonLat {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *NearestRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *NearestRequest) GetFilter() *PlaceFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type NearestBatchRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Locations []*LonLat              `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty"`
+	// Maximum number of places per location, defaults to 1.
+	Limit         int32        `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Filter        *PlaceFilter `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NearestBatchRequest) Reset() {
+	*x = NearestBatchRequest{}
+	mi := &file_geonames_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NearestBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NearestBatchRequest) ProtoMessage() {}
+
+func (x *NearestBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NearestBatchRequest.ProtoReflect.Descriptor instead.
+func (*NearestBatchRequest) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *NearestBatchRequest) GetLocations() []*LonLat {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+func (x *NearestBatchRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *NearestBatchRequest) GetFilter() *PlaceFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type NearestBatchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Index into NearestBatchRequest.locations.
+	Index         int32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Places        []*Place `protobuf:"bytes,2,rep,name=places,proto3" json:"places,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NearestBatchResult) Reset() {
+	*x = NearestBatchResult{}
+	mi := &file_geonames_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NearestBatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NearestBatchResult) ProtoMessage() {}
+
+func (x *NearestBatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NearestBatchResult.ProtoReflect.Descriptor instead.
+func (*NearestBatchResult) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *NearestBatchResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *NearestBatchResult) GetPlaces() []*Place {
+	if x != nil {
+		return x.Places
+	}
+	return nil
+}
+
+type SearchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Maximum number of places returned, defaults to 10.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Whether to also match misspelled or similar-sounding names, if the server supports it.
+	Fuzzy         bool `protobuf:"varint,3,opt,name=fuzzy,proto3" json:"fuzzy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_geonames_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetFuzzy() bool {
+	if x != nil {
+		return x.Fuzzy
+	}
+	return false
+}
+
+type PostalRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ISO-3166 alpha-2 country code, required for code and prefix lookups.
+	CountryCode string `protobuf:"bytes,1,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	// Types that are valid to be assigned to By:
+	//
+	//	*PostalRequest_Code
+	//	*PostalRequest_Prefix
+	//	*PostalRequest_Near
+	By isPostalRequest_By `protobuf_oneof:"by"`
+	// Maximum number of postal codes returned for prefix and nearest lookups, defaults to 10.
+	Limit         int32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostalRequest) Reset() {
+	*x = PostalRequest{}
+	mi := &file_geonames_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostalRequest) ProtoMessage() {}
+
+func (x *PostalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostalRequest.ProtoReflect.Descriptor instead.
+func (*PostalRequest) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PostalRequest) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *PostalRequest) GetBy() isPostalRequest_By {
+	if x != nil {
+		return x.By
+	}
+	return nil
+}
+
+func (x *PostalRequest) GetCode() string {
+	if x != nil {
+		if x, ok := x.By.(*PostalRequest_Code); ok {
+			return x.Code
+		}
+	}
+	return ""
+}
+
+func (x *PostalRequest) GetPrefix() string {
+	if x != nil {
+		if x, ok := x.By.(*PostalRequest_Prefix); ok {
+			return x.Prefix
+		}
+	}
+	return ""
+}
+
+func (x *PostalRequest) GetNear() *LonLat {
+	if x != nil {
+		if x, ok := x.By.(*PostalRequest_Near); ok {
+			return x.Near
+		}
+	}
+	return nil
+}
+
+func (x *PostalRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type isPostalRequest_By interface {
+	isPostalRequest_By()
+}
+
+type PostalRequest_Code struct {
+	Code string `protobuf:"bytes,2,opt,name=code,proto3,oneof"`
+}
+
+type PostalRequest_Prefix struct {
+	Prefix string `protobuf:"bytes,3,opt,name=prefix,proto3,oneof"`
+}
+
+type PostalRequest_Near struct {
+	Near *LonLat `protobuf:"bytes,4,opt,name=near,proto3,oneof"`
+}
+
+func (*PostalRequest_Code) isPostalRequest_By() {}
+
+func (*PostalRequest_Prefix) isPostalRequest_By() {}
+
+func (*PostalRequest_Near) isPostalRequest_By() {}
+
+type Postal struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	CountryCode string                 `protobuf:"bytes,1,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	Code        string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	PlaceName   string                 `protobuf:"bytes,3,opt,name=place_name,json=placeName,proto3" json:"place_name,omitempty"`
+	Location    *LonLat                `protobuf:"bytes,4,opt,name=location,proto3" json:"location,omitempty"`
+	// Admin codes mapped to their names.
+	Admins   map[string]string `protobuf:"bytes,5,rep,name=admins,proto3" json:"admins,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Accuracy int64             `protobuf:"varint,6,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	// Set for nearest lookups.
+	DistanceKm    float64 `protobuf:"fixed64,7,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Postal) Reset() {
+	*x = Postal{}
+	mi := &file_geonames_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Postal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Postal) ProtoMessage() {}
+
+func (x *Postal) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Postal.ProtoReflect.Descriptor instead.
+func (*Postal) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Postal) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *Postal) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Postal) GetPlaceName() string {
+	if x != nil {
+		return x.PlaceName
+	}
+	return ""
+}
+
+func (x *Postal) GetLocation() *LonLat {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *Postal) GetAdmins() map[string]string {
+	if x != nil {
+		return x.Admins
+	}
+	return nil
+}
+
+func (x *Postal) GetAccuracy() int64 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+func (x *Postal) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+type PostalsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Postals       []*Postal              `protobuf:"bytes,1,rep,name=postals,proto3" json:"postals,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostalsResponse) Reset() {
+	*x = PostalsResponse{}
+	mi := &file_geonames_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostalsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostalsResponse) ProtoMessage() {}
+
+func (x *PostalsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostalsResponse.ProtoReflect.Descriptor instead.
+func (*PostalsResponse) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PostalsResponse) GetPostals() []*Postal {
+	if x != nil {
+		return x.Postals
+	}
+	return nil
+}
+
+type CountryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountryRequest) Reset() {
+	*x = CountryRequest{}
+	mi := &file_geonames_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountryRequest) ProtoMessage() {}
+
+func (x *CountryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountryRequest.ProtoReflect.Descriptor instead.
+func (*CountryRequest) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CountryRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type CountryInfo struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Iso2             string                 `protobuf:"bytes,1,opt,name=iso2,proto3" json:"iso2,omitempty"`
+	Iso3             string                 `protobuf:"bytes,2,opt,name=iso3,proto3" json:"iso3,omitempty"`
+	IsoNumeric       string                 `protobuf:"bytes,3,opt,name=iso_numeric,json=isoNumeric,proto3" json:"iso_numeric,omitempty"`
+	Fips             string                 `protobuf:"bytes,4,opt,name=fips,proto3" json:"fips,omitempty"`
+	Name             string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	Capital          string                 `protobuf:"bytes,6,opt,name=capital,proto3" json:"capital,omitempty"`
+	AreaSqKm         int64                  `protobuf:"varint,7,opt,name=area_sq_km,json=areaSqKm,proto3" json:"area_sq_km,omitempty"`
+	Population       int64                  `protobuf:"varint,8,opt,name=population,proto3" json:"population,omitempty"`
+	Continent        string                 `protobuf:"bytes,9,opt,name=continent,proto3" json:"continent,omitempty"`
+	Tld              string                 `protobuf:"bytes,10,opt,name=tld,proto3" json:"tld,omitempty"`
+	CurrencyCode     string                 `protobuf:"bytes,11,opt,name=currency_code,json=currencyCode,proto3" json:"currency_code,omitempty"`
+	CurrencyName     string                 `protobuf:"bytes,12,opt,name=currency_name,json=currencyName,proto3" json:"currency_name,omitempty"`
+	CallingCode      string                 `protobuf:"bytes,13,opt,name=calling_code,json=callingCode,proto3" json:"calling_code,omitempty"`
+	PostalCodeFormat string                 `protobuf:"bytes,14,opt,name=postal_code_format,json=postalCodeFormat,proto3" json:"postal_code_format,omitempty"`
+	PostalCodeRegex  string                 `protobuf:"bytes,15,opt,name=postal_code_regex,json=postalCodeRegex,proto3" json:"postal_code_regex,omitempty"`
+	Languages        []string               `protobuf:"bytes,16,rep,name=languages,proto3" json:"languages,omitempty"`
+	// ISO-3166 alpha-2 codes of all neighboring countries.
+	Neighbors     []string `protobuf:"bytes,17,rep,name=neighbors,proto3" json:"neighbors,omitempty"`
+	GeonameId     int64    `protobuf:"varint,18,opt,name=geoname_id,json=geonameId,proto3" json:"geoname_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountryInfo) Reset() {
+	*x = CountryInfo{}
+	mi := &file_geonames_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountryInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountryInfo) ProtoMessage() {}
+
+func (x *CountryInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountryInfo.ProtoReflect.Descriptor instead.
+func (*CountryInfo) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CountryInfo) GetIso2() string {
+	if x != nil {
+		return x.Iso2
+	}
+	return ""
+}
+
+func (x *CountryInfo) GetIso3() string {
+	if x != nil {
+		return x.Iso3
+	}
+	return ""
+}
+
+func (x *CountryInfo) GetIsoNumeric() string {
+	if x != nil {
+		return x.IsoNumeric
+	}
+	return ""
+}
+
+func (x *CountryInfo) GetFips() string {
+	if x != nil {
+		return x.Fips
+	}
+	return ""
+}
+
+func (x *CountryInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CountryInfo) GetCapital() string {
+	if x != nil {
+		return x.Capital
+	}
+	return ""
+}
+
+func (x *CountryInfo) GetAreaSqKm() int64 {
+	if x != nil {
+		return x.AreaSqKm
+	}
+	return 0
+}
+
+func (x *CountryInfo) GetPopulation() int64 {
+	if x != nil {
+		return x.Population
+	}
+	return 0
+}
+
+func (x *CountryInfo) GetContinent() string {
+	if x != nil {
+		return x.Continent
+	}
+	return ""
+}
+
+func (x *CountryInfo) GetTld() string {
+	if x != nil {
+		return x.Tld
+	}
+	return ""
+}
+
+func (x *CountryInfo) GetCurrencyCode() string {
+	if x != nil {
+		return x.CurrencyCode
+	}
+	return ""
+}
+
+func (x *CountryInfo) GetCurrencyName() string {
+	if x != nil {
+		return x.CurrencyName
+	}
+	return ""
+}
+
+func (x *CountryInfo) GetCallingCode() string {
+	if x != nil {
+		return x.CallingCode
+	}
+	return ""
+}
+
+func (x *CountryInfo) GetPostalCodeFormat() string {
+	if x != nil {
+		return x.PostalCodeFormat
+	}
+	return ""
+}
+
+func (x *CountryInfo) GetPostalCodeRegex() string {
+	if x != nil {
+		return x.PostalCodeRegex
+	}
+	return ""
+}
+
+func (x *CountryInfo) GetLanguages() []string {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *CountryInfo) GetNeighbors() []string {
+	if x != nil {
+		return x.Neighbors
+	}
+	return nil
+}
+
+func (x *CountryInfo) GetGeonameId() int64 {
+	if x != nil {
+		return x.GeonameId
+	}
+	return 0
+}
+
+type AdminChainRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// If set, the divisions of this place are returned and the other fields are ignored.
+	PlaceId     int64  `protobuf:"varint,1,opt,name=place_id,json=placeId,proto3" json:"place_id,omitempty"`
+	CountryCode string `protobuf:"bytes,2,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	// Admin codes 1 through 4, as in the place records.
+	AdminCodes    []string `protobuf:"bytes,3,rep,name=admin_codes,json=adminCodes,proto3" json:"admin_codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminChainRequest) Reset() {
+	*x = AdminChainRequest{}
+	mi := &file_geonames_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminChainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminChainRequest) ProtoMessage() {}
+
+func (x *AdminChainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminChainRequest.ProtoReflect.Descriptor instead.
+func (*AdminChainRequest) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AdminChainRequest) GetPlaceId() int64 {
+	if x != nil {
+		return x.PlaceId
+	}
+	return 0
+}
+
+func (x *AdminChainRequest) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *AdminChainRequest) GetAdminCodes() []string {
+	if x != nil {
+		return x.AdminCodes
+	}
+	return nil
+}
+
+type AdminChainResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Known divisions, most-general first.
+	Divisions     []*AdminDivision `protobuf:"bytes,1,rep,name=divisions,proto3" json:"divisions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminChainResponse) Reset() {
+	*x = AdminChainResponse{}
+	mi := &file_geonames_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminChainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminChainResponse) ProtoMessage() {}
+
+func (x *AdminChainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminChainResponse.ProtoReflect.Descriptor instead.
+func (*AdminChainResponse) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AdminChainResponse) GetDivisions() []*AdminDivision {
+	if x != nil {
+		return x.Divisions
+	}
+	return nil
+}
+
+type TimezoneRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Location *LonLat                `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	// Defaults to the current time.
+	At            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=at,proto3" json:"at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimezoneRequest) Reset() {
+	*x = TimezoneRequest{}
+	mi := &file_geonames_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimezoneRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimezoneRequest) ProtoMessage() {}
+
+func (x *TimezoneRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimezoneRequest.ProtoReflect.Descriptor instead.
+func (*TimezoneRequest) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *TimezoneRequest) GetLocation() *LonLat {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *TimezoneRequest) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+type TimezoneResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// IANA time zone name, eg. "Europe/Berlin".
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	OffsetSeconds int32  `protobuf:"varint,2,opt,name=offset_seconds,json=offsetSeconds,proto3" json:"offset_seconds,omitempty"`
+	Abbrev        string `protobuf:"bytes,3,opt,name=abbrev,proto3" json:"abbrev,omitempty"`
+	Dst           bool   `protobuf:"varint,4,opt,name=dst,proto3" json:"dst,omitempty"`
+	// The place whose time zone was used, 0 if derived from the country.
+	PlaceId       int64   `protobuf:"varint,5,opt,name=place_id,json=placeId,proto3" json:"place_id,omitempty"`
+	CountryCode   string  `protobuf:"bytes,6,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	DistanceKm    float64 `protobuf:"fixed64,7,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimezoneResponse) Reset() {
+	*x = TimezoneResponse{}
+	mi := &file_geonames_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimezoneResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimezoneResponse) ProtoMessage() {}
+
+func (x *TimezoneResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_geonames_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimezoneResponse.ProtoReflect.Descriptor instead.
+func (*TimezoneResponse) Descriptor() ([]byte, []int) {
+	return file_geonames_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TimezoneResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TimezoneResponse) GetOffsetSeconds() int32 {
+	if x != nil {
+		return x.OffsetSeconds
+	}
+	return 0
+}
+
+func (x *TimezoneResponse) GetAbbrev() string {
+	if x != nil {
+		return x.Abbrev
+	}
+	return ""
+}
+
+func (x *TimezoneResponse) GetDst() bool {
+	if x != nil {
+		return x.Dst
+	}
+	return false
+}
+
+func (x *TimezoneResponse) GetPlaceId() int64 {
+	if x != nil {
+		return x.PlaceId
+	}
+	return 0
+}
+
+func (x *TimezoneResponse) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *TimezoneResponse) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+var File_geonames_proto protoreflect.FileDescriptor
+
+const file_geonames_proto_rawDesc = "" +
+	"\n" +
+	"\x0egeonames.proto\x12\vgeonames.v1\x1a\x1fgoogle/protobuf/timestamp.proto\",\n" +
+	"\x06LonLat\x12\x10\n" +
+	"\x03lon\x18\x01 \x01(\x01R\x03lon\x12\x10\n" +
+	"\x03lat\x18\x02 \x01(\x01R\x03lat\"\xcf\x01\n" +
+	"\vPlaceFilter\x12'\n" +
+	"\x0ffeature_classes\x18\x01 \x01(\tR\x0efeatureClasses\x12#\n" +
+	"\rfeature_codes\x18\x02 \x03(\tR\ffeatureCodes\x12#\n" +
+	"\rcountry_codes\x18\x03 \x03(\tR\fcountryCodes\x12%\n" +
+	"\x0emin_population\x18\x04 \x01(\x03R\rminPopulation\x12&\n" +
+	"\x0fmax_distance_km\x18\x05 \x01(\x01R\rmaxDistanceKm\"|\n" +
+	"\rAdminDivision\x12\x14\n" +
+	"\x05level\x18\x01 \x01(\x05R\x05level\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"name_ascii\x18\x05 \x01(\tR\tnameAscii\"\xce\x03\n" +
+	"\x05Place\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"name_ascii\x18\x03 \x01(\tR\tnameAscii\x12/\n" +
+	"\blocation\x18\x04 \x01(\v2\x13.geonames.v1.LonLatR\blocation\x12#\n" +
+	"\rfeature_class\x18\x05 \x01(\tR\ffeatureClass\x12!\n" +
+	"\ffeature_code\x18\x06 \x01(\tR\vfeatureCode\x12!\n" +
+	"\fcountry_code\x18\a \x01(\tR\vcountryCode\x122\n" +
+	"\x06admins\x18\b \x03(\v2\x1a.geonames.v1.AdminDivisionR\x06admins\x12\x1e\n" +
+	"\n" +
+	"population\x18\t \x01(\x03R\n" +
+	"population\x12\x1c\n" +
+	"\televation\x18\n" +
+	" \x01(\x03R\televation\x12\x1a\n" +
+	"\btimezone\x18\v \x01(\tR\btimezone\x12\x1f\n" +
+	"\vdistance_km\x18\f \x01(\x01R\n" +
+	"distanceKm\x12\x14\n" +
+	"\x05score\x18\r \x01(\x01R\x05score\x12!\n" +
+	"\fmatched_name\x18\x0e \x01(\tR\vmatchedName\"<\n" +
+	"\x0ePlacesResponse\x12*\n" +
+	"\x06places\x18\x01 \x03(\v2\x12.geonames.v1.PlaceR\x06places\"\x89\x01\n" +
+	"\x0eNearestRequest\x12/\n" +
+	"\blocation\x18\x01 \x01(\v2\x13.geonames.v1.LonLatR\blocation\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x120\n" +
+	"\x06filter\x18\x03 \x01(\v2\x18.geonames.v1.PlaceFilterR\x06filter\"\x90\x01\n" +
+	"\x13NearestBatchRequest\x121\n" +
+	"\tlocations\x18\x01 \x03(\v2\x13.geonames.v1.LonLatR\tlocations\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x120\n" +
+	"\x06filter\x18\x03 \x01(\v2\x18.geonames.v1.PlaceFilterR\x06filter\"V\n" +
+	"\x12NearestBatchResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12*\n" +
+	"\x06places\x18\x02 \x03(\v2\x12.geonames.v1.PlaceR\x06places\"Q\n" +
+	"\rSearchRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x14\n" +
+	"\x05fuzzy\x18\x03 \x01(\bR\x05fuzzy\"\xa9\x01\n" +
+	"\rPostalRequest\x12!\n" +
+	"\fcountry_code\x18\x01 \x01(\tR\vcountryCode\x12\x14\n" +
+	"\x04code\x18\x02 \x01(\tH\x00R\x04code\x12\x18\n" +
+	"\x06prefix\x18\x03 \x01(\tH\x00R\x06prefix\x12)\n" +
+	"\x04near\x18\x04 \x01(\v2\x13.geonames.v1.LonLatH\x00R\x04near\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limitB\x04\n" +
+	"\x02by\"\xc0\x02\n" +
+	"\x06Postal\x12!\n" +
+	"\fcountry_code\x18\x01 \x01(\tR\vcountryCode\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x1d\n" +
+	"\n" +
+	"place_name\x18\x03 \x01(\tR\tplaceName\x12/\n" +
+	"\blocation\x18\x04 \x01(\v2\x13.geonames.v1.LonLatR\blocation\x127\n" +
+	"\x06admins\x18\x05 \x03(\v2\x1f.geonames.v1.Postal.AdminsEntryR\x06admins\x12\x1a\n" +
+	"\baccuracy\x18\x06 \x01(\x03R\baccuracy\x12\x1f\n" +
+	"\vdistance_km\x18\a \x01(\x01R\n" +
+	"distanceKm\x1a9\n" +
+	"\vAdminsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"@\n" +
+	"\x0fPostalsResponse\x12-\n" +
+	"\apostals\x18\x01 \x03(\v2\x13.geonames.v1.PostalR\apostals\"$\n" +
+	"\x0eCountryRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"\xa8\x04\n" +
+	"\vCountryInfo\x12\x12\n" +
+	"\x04iso2\x18\x01 \x01(\tR\x04iso2\x12\x12\n" +
+	"\x04iso3\x18\x02 \x01(\tR\x04iso3\x12\x1f\n" +
+	"\viso_numeric\x18\x03 \x01(\tR\n" +
+	"isoNumeric\x12\x12\n" +
+	"\x04fips\x18\x04 \x01(\tR\x04fips\x12\x12\n" +
+	"\x04name\x18\x05 \x01(\tR\x04name\x12\x18\n" +
+	"\acapital\x18\x06 \x01(\tR\acapital\x12\x1c\n" +
+	"\n" +
+	"area_sq_km\x18\a \x01(\x03R\bareaSqKm\x12\x1e\n" +
+	"\n" +
+	"population\x18\b \x01(\x03R\n" +
+	"population\x12\x1c\n" +
+	"\tcontinent\x18\t \x01(\tR\tcontinent\x12\x10\n" +
+	"\x03tld\x18\n" +
+	" \x01(\tR\x03tld\x12#\n" +
+	"\rcurrency_code\x18\v \x01(\tR\fcurrencyCode\x12#\n" +
+	"\rcurrency_name\x18\f \x01(\tR\fcurrencyName\x12!\n" +
+	"\fcalling_code\x18\r \x01(\tR\vcallingCode\x12,\n" +
+	"\x12postal_code_format\x18\x0e \x01(\tR\x10postalCodeFormat\x12*\n" +
+	"\x11postal_code_regex\x18\x0f \x01(\tR\x0fpostalCodeRegex\x12\x1c\n" +
+	"\tlanguages\x18\x10 \x03(\tR\tlanguages\x12\x1c\n" +
+	"\tneighbors\x18\x11 \x03(\tR\tneighbors\x12\x1d\n" +
+	"\n" +
+	"geoname_id\x18\x12 \x01(\x03R\tgeonameId\"r\n" +
+	"\x11AdminChainRequest\x12\x19\n" +
+	"\bplace_id\x18\x01 \x01(\x03R\aplaceId\x12!\n" +
+	"\fcountry_code\x18\x02 \x01(\tR\vcountryCode\x12\x1f\n" +
+	"\vadmin_codes\x18\x03 \x03(\tR\n" +
+	"adminCodes\"N\n" +
+	"\x12AdminChainResponse\x128\n" +
+	"\tdivisions\x18\x01 \x03(\v2\x1a.geonames.v1.AdminDivisionR\tdivisions\"n\n" +
+	"\x0fTimezoneRequest\x12/\n" +
+	"\blocation\x18\x01 \x01(\v2\x13.geonames.v1.LonLatR\blocation\x12*\n" +
+	"\x02at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\"\xd6\x01\n" +
+	"\x10TimezoneResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12%\n" +
+	"\x0eoffset_seconds\x18\x02 \x01(\x05R\roffsetSeconds\x12\x16\n" +
+	"\x06abbrev\x18\x03 \x01(\tR\x06abbrev\x12\x10\n" +
+	"\x03dst\x18\x04 \x01(\bR\x03dst\x12\x19\n" +
+	"\bplace_id\x18\x05 \x01(\x03R\aplaceId\x12!\n" +
+	"\fcountry_code\x18\x06 \x01(\tR\vcountryCode\x12\x1f\n" +
+	"\vdistance_km\x18\a \x01(\x01R\n" +
+	"distanceKm2\x85\x04\n" +
+	"\bGeonames\x12C\n" +
+	"\aNearest\x12\x1b.geonames.v1.NearestRequest\x1a\x1b.geonames.v1.PlacesResponse\x12S\n" +
+	"\fNearestBatch\x12 .geonames.v1.NearestBatchRequest\x1a\x1f.geonames.v1.NearestBatchResult0\x01\x12A\n" +
+	"\x06Search\x12\x1a.geonames.v1.SearchRequest\x1a\x1b.geonames.v1.PlacesResponse\x12B\n" +
+	"\x06Postal\x12\x1a.geonames.v1.PostalRequest\x1a\x1c.geonames.v1.PostalsResponse\x12@\n" +
+	"\aCountry\x12\x1b.geonames.v1.CountryRequest\x1a\x18.geonames.v1.CountryInfo\x12M\n" +
+	"\n" +
+	"AdminChain\x12\x1e.geonames.v1.AdminChainRequest\x1a\x1f.geonames.v1.AdminChainResponse\x12G\n" +
+	"\bTimezone\x12\x1c.geonames.v1.TimezoneRequest\x1a\x1d.geonames.v1.TimezoneResponseB3Z1github.com/go-geo/geonames/grpc-api;geonames_grpcb\x06proto3"
+
+var (
+	file_geonames_proto_rawDescOnce sync.Once
+	file_geonames_proto_rawDescData []byte
+)
+
+func file_geonames_proto_rawDescGZIP() []byte {
+	file_geonames_proto_rawDescOnce.Do(func() {
+		file_geonames_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_geonames_proto_rawDesc), len(file_geonames_proto_rawDesc)))
+	})
+	return file_geonames_proto_rawDescData
+}
+
+var file_geonames_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_geonames_proto_goTypes = []any{
+	(*LonLat)(nil),                // 0: geonames.v1.LonLat
+	(*PlaceFilter)(nil),           // 1: geonames.v1.PlaceFilter
+	(*AdminDivision)(nil),         // 2: geonames.v1.AdminDivision
+	(*Place)(nil),                 // 3: geonames.v1.Place
+	(*PlacesResponse)(nil),        // 4: geonames.v1.PlacesResponse
+	(*NearestRequest)(nil),        // 5: geonames.v1.NearestRequest
+	(*NearestBatchRequest)(nil),   // 6: geonames.v1.NearestBatchRequest
+	(*NearestBatchResult)(nil),    // 7: geonames.v1.NearestBatchResult
+	(*SearchRequest)(nil),         // 8: geonames.v1.SearchRequest
+	(*PostalRequest)(nil),         // 9: geonames.v1.PostalRequest
+	(*Postal)(nil),                // 10: geonames.v1.Postal
+	(*PostalsResponse)(nil),       // 11: geonames.v1.PostalsResponse
+	(*CountryRequest)(nil),        // 12: geonames.v1.CountryRequest
+	(*CountryInfo)(nil),           // 13: geonames.v1.CountryInfo
+	(*AdminChainRequest)(nil),     // 14: geonames.v1.AdminChainRequest
+	(*AdminChainResponse)(nil),    // 15: geonames.v1.AdminChainResponse
+	(*TimezoneRequest)(nil),       // 16: geonames.v1.TimezoneRequest
+	(*TimezoneResponse)(nil),      // 17: geonames.v1.TimezoneResponse
+	nil,                           // 18: geonames.v1.Postal.AdminsEntry
+	(*timestamppb.Timestamp)(nil), // 19: google.protobuf.Timestamp
+}
+var file_geonames_proto_depIdxs = []int32{
+	0,  // 0: geonames.v1.Place.location:type_name -> geonames.v1.LonLat
+	2,  // 1: geonames.v1.Place.admins:type_name -> geonames.v1.AdminDivision
+	3,  // 2: geonames.v1.PlacesResponse.places:type_name -> geonames.v1.Place
+	0,  // 3: geonames.v1.NearestRequest.location:type_name -> geonames.v1.LonLat
+	1,  // 4: geonames.v1.NearestRequest.filter:type_name -> geonames.v1.PlaceFilter
+	0,  // 5: geonames.v1.NearestBatchRequest.locations:type_name -> geonames.v1.LonLat
+	1,  // 6: geonames.v1.NearestBatchRequest.filter:type_name -> geonames.v1.PlaceFilter
+	3,  // 7: geonames.v1.NearestBatchResult.places:type_name -> geonames.v1.Place
+	0,  // 8: geonames.v1.PostalRequest.near:type_name -> geonames.v1.LonLat
+	0,  // 9: geonames.v1.Postal.location:type_name -> geonames.v1.LonLat
+	18, // 10: geonames.v1.Postal.admins:type_name -> geonames.v1.Postal.AdminsEntry
+	10, // 11: geonames.v1.PostalsResponse.postals:type_name -> geonames.v1.Postal
+	2,  // 12: geonames.v1.AdminChainResponse.divisions:type_name -> geonames.v1.AdminDivision
+	0,  // 13: geonames.v1.TimezoneRequest.location:type_name -> geonames.v1.LonLat
+	19, // 14: geonames.v1.TimezoneRequest.at:type_name -> google.protobuf.Timestamp
+	5,  // 15: geonames.v1.Geonames.Nearest:input_type -> geonames.v1.NearestRequest
+	6,  // 16: geonames.v1.Geonames.NearestBatch:input_type -> geonames.v1.NearestBatchRequest
+	8,  // 17: geonames.v1.Geonames.Search:input_type -> geonames.v1.SearchRequest
+	9,  // 18: geonames.v1.Geonames.Postal:input_type -> geonames.v1.PostalRequest
+	12, // 19: geonames.v1.Geonames.Country:input_type -> geonames.v1.CountryRequest
+	14, // 20: geonames.v1.Geonames.AdminChain:input_type -> geonames.v1.AdminChainRequest
+	16, // 21: geonames.v1.Geonames.Timezone:input_type -> geonames.v1.TimezoneRequest
+	4,  // 22: geonames.v1.Geonames.Nearest:output_type -> geonames.v1.PlacesResponse
+	7,  // 23: geonames.v1.Geonames.NearestBatch:output_type -> geonames.v1.NearestBatchResult
+	4,  // 24: geonames.v1.Geonames.Search:output_type -> geonames.v1.PlacesResponse
+	11, // 25: geonames.v1.Geonames.Postal:output_type -> geonames.v1.PostalsResponse
+	13, // 26: geonames.v1.Geonames.Country:output_type -> geonames.v1.CountryInfo
+	15, // 27: geonames.v1.Geonames.AdminChain:output_type -> geonames.v1.AdminChainResponse
+	17, // 28: geonames.v1.Geonames.Timezone:output_type -> geonames.v1.TimezoneResponse
+	22, // [22:29] is the sub-list for method output_type
+	15, // [15:22] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_geonames_proto_init() }
+func file_geonames_proto_init() {
+	if File_geonames_proto != nil {
+		return
+	}
+	file_geonames_proto_msgTypes[9].OneofWrappers = []any{
+		(*PostalRequest_Code)(nil),
+		(*PostalRequest_Prefix)(nil),
+		(*PostalRequest_Near)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_geonames_proto_rawDesc), len(file_geonames_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_geonames_proto_goTypes,
+		DependencyIndexes: file_geonames_proto_depIdxs,
+		MessageInfos:      file_geonames_proto_msgTypes,
+	}.Build()
+	File_geonames_proto = out.File
+	file_geonames_proto_goTypes = nil
+	file_geonames_proto_depIdxs = nil
+}
@@ -0,0 +1,370 @@
+// Lookups over parsed GeoNames dumps: nearest places (single and batched), name search,
+// postal codes, countries, administrative divisions and time zones.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: geonames.proto
+
+package geonames_grpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Geonames_Nearest_FullMethodName      = "/geonames.v1.Geonames/Nearest"
+	Geonames_NearestBatch_FullMethodName = "/geonames.v1.Geonames/NearestBatch"
+	Geonames_Search_FullMethodName       = "/geonames.v1.Geonames/Search"
+	Geonames_Postal_FullMethodName       = "/geonames.v1.Geonames/Postal"
+	Geonames_Country_FullMethodName      = "/geonames.v1.Geonames/Country"
+	Geonames_AdminChain_FullMethodName   = "/geonames.v1.Geonames/AdminChain"
+	Geonames_Timezone_FullMethodName     = "/geonames.v1.Geonames/Timezone"
+)
+
+// GeonamesClient is the client API for Geonames service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GeonamesClient interface {
+	// Returns the places nearest to a coordinate, nearest first.
+	Nearest(ctx context.Context, in *NearestRequest, opts ...grpc.CallOption) (*PlacesResponse, error)
+	// Reverse-geocodes a batch of coordinates, streaming one result per coordinate (in request order).
+	NearestBatch(ctx context.Context, in *NearestBatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NearestBatchResult], error)
+	// Searches places by name, optionally qualified as in "Springfield, IL, US".
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*PlacesResponse, error)
+	// Looks up postal codes by code, by code prefix, or nearest to a coordinate.
+	Postal(ctx context.Context, in *PostalRequest, opts ...grpc.CallOption) (*PostalsResponse, error)
+	// Looks up a country by ISO-3166 alpha-2, alpha-3 or numeric code, FIPS code or name.
+	Country(ctx context.Context, in *CountryRequest, opts ...grpc.CallOption) (*CountryInfo, error)
+	// Resolves the first- to fourth-order administrative divisions of a place or admin codes.
+	AdminChain(ctx context.Context, in *AdminChainRequest, opts ...grpc.CallOption) (*AdminChainResponse, error)
+	// Resolves a coordinate to its IANA time zone, with UTC offset and DST status at a given time.
+	Timezone(ctx context.Context, in *TimezoneRequest, opts ...grpc.CallOption) (*TimezoneResponse, error)
+}
+
+type geonamesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGeonamesClient(cc grpc.ClientConnInterface) GeonamesClient {
+	return &geonamesClient{cc}
+}
+
+func (c *geonamesClient) Nearest(ctx context.Context, in *NearestRequest, opts ...grpc.CallOption) (*PlacesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PlacesResponse)
+	err := c.cc.Invoke(ctx, Geonames_Nearest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geonamesClient) NearestBatch(ctx context.Context, in *NearestBatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NearestBatchResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Geonames_ServiceDesc.Streams[0], Geonames_NearestBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[NearestBatchRequest, NearestBatchResult]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Geonames_NearestBatchClient = grpc.ServerStreamingClient[NearestBatchResult]
+
+func (c *geonamesClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*PlacesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PlacesResponse)
+	err := c.cc.Invoke(ctx, Geonames_Search_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geonamesClient) Postal(ctx context.Context, in *PostalRequest, opts ...grpc.CallOption) (*PostalsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PostalsResponse)
+	err := c.cc.Invoke(ctx, Geonames_Postal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geonamesClient) Country(ctx context.Context, in *CountryRequest, opts ...grpc.CallOption) (*CountryInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountryInfo)
+	err := c.cc.Invoke(ctx, Geonames_Country_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geonamesClient) AdminChain(ctx context.Context, in *AdminChainRequest, opts ...grpc.CallOption) (*AdminChainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminChainResponse)
+	err := c.cc.Invoke(ctx, Geonames_AdminChain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *geonamesClient) Timezone(ctx context.Context, in *TimezoneRequest, opts ...grpc.CallOption) (*TimezoneResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TimezoneResponse)
+	err := c.cc.Invoke(ctx, Geonames_Timezone_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GeonamesServer is the server API for Geonames service.
+// All implementations must embed UnimplementedGeonamesServer
+// for forward compatibility.
+type GeonamesServer interface {
+	// Returns the places nearest to a coordinate, nearest first.
+	Nearest(context.Context, *NearestRequest) (*PlacesResponse, error)
+	// Reverse-geocodes a batch of coordinates, streaming one result per coordinate (in request order).
+	NearestBatch(*NearestBatchRequest, grpc.ServerStreamingServer[NearestBatchResult]) error
+	// Searches places by name, optionally qualified as in "Springfield, IL, US".
+	Search(context.Context, *SearchRequest) (*PlacesResponse, error)
+	// Looks up postal codes by code, by code prefix, or nearest to a coordinate.
+	Postal(context.Context, *PostalRequest) (*PostalsResponse, error)
+	// Looks up a country by ISO-3166 alpha-2, alpha-3 or numeric code, FIPS code or name.
+	Country(context.Context, *CountryRequest) (*CountryInfo, error)
+	// Resolves the first- to fourth-order administrative divisions of a place or admin codes.
+	AdminChain(context.Context, *AdminChainRequest) (*AdminChainResponse, error)
+	// Resolves a coordinate to its IANA time zone, with UTC offset and DST status at a given time.
+	Timezone(context.Context, *TimezoneRequest) (*TimezoneResponse, error)
+	mustEmbedUnimplementedGeonamesServer()
+}
+
+// UnimplementedGeonamesServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGeonamesServer struct{}
+
+func (UnimplementedGeonamesServer) Nearest(context.Context, *NearestRequest) (*PlacesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Nearest not implemented")
+}
+func (UnimplementedGeonamesServer) NearestBatch(*NearestBatchRequest, grpc.ServerStreamingServer[NearestBatchResult]) error {
+	return status.Error(codes.Unimplemented, "method NearestBatch not implemented")
+}
+func (UnimplementedGeonamesServer) Search(context.Context, *SearchRequest) (*PlacesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedGeonamesServer) Postal(context.Context, *PostalRequest) (*PostalsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Postal not implemented")
+}
+func (UnimplementedGeonamesServer) Country(context.Context, *CountryRequest) (*CountryInfo, error) {
+	return nil, status.Error(codes.Unimplemented, "method Country not implemented")
+}
+func (UnimplementedGeonamesServer) AdminChain(context.Context, *AdminChainRequest) (*AdminChainResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AdminChain not implemented")
+}
+func (UnimplementedGeonamesServer) Timezone(context.Context, *TimezoneRequest) (*TimezoneResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Timezone not implemented")
+}
+func (UnimplementedGeonamesServer) mustEmbedUnimplementedGeonamesServer() {}
+func (UnimplementedGeonamesServer) testEmbeddedByValue()                  {}
+
+// UnsafeGeonamesServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GeonamesServer will
+// result in compilation errors.
+type UnsafeGeonamesServer interface {
+	mustEmbedUnimplementedGeonamesServer()
+}
+
+func RegisterGeonamesServer(s grpc.ServiceRegistrar, srv GeonamesServer) {
+	// If the following call panics, it indicates UnimplementedGeonamesServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Geonames_ServiceDesc, srv)
+}
+
+func _Geonames_Nearest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NearestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeonamesServer).Nearest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Geonames_Nearest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeonamesServer).Nearest(ctx, req.(*NearestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Geonames_NearestBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(NearestBatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GeonamesServer).NearestBatch(m, &grpc.GenericServerStream[NearestBatchRequest, NearestBatchResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Geonames_NearestBatchServer = grpc.ServerStreamingServer[NearestBatchResult]
+
+func _Geonames_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeonamesServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Geonames_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeonamesServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Geonames_Postal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PostalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeonamesServer).Postal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Geonames_Postal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeonamesServer).Postal(ctx, req.(*PostalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Geonames_Country_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeonamesServer).Country(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Geonames_Country_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeonamesServer).Country(ctx, req.(*CountryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Geonames_AdminChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminChainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeonamesServer).AdminChain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Geonames_AdminChain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeonamesServer).AdminChain(ctx, req.(*AdminChainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Geonames_Timezone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimezoneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeonamesServer).Timezone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Geonames_Timezone_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeonamesServer).Timezone(ctx, req.(*TimezoneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Geonames_ServiceDesc is the grpc.ServiceDesc for Geonames service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Geonames_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "geonames.v1.Geonames",
+	HandlerType: (*GeonamesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Nearest",
+			Handler:    _Geonames_Nearest_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _Geonames_Search_Handler,
+		},
+		{
+			MethodName: "Postal",
+			Handler:    _Geonames_Postal_Handler,
+		},
+		{
+			MethodName: "Country",
+			Handler:    _Geonames_Country_Handler,
+		},
+		{
+			MethodName: "AdminChain",
+			Handler:    _Geonames_AdminChain_Handler,
+		},
+		{
+			MethodName: "Timezone",
+			Handler:    _Geonames_Timezone_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "NearestBatch",
+			Handler:       _Geonames_NearestBatch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "geonames.proto",
+}
@@ -0,0 +1,245 @@
+//	Protocol-buffer definitions (`geonames.proto`) and a gRPC server for nearest-place, search, postal-code, country,
+//	admin-division and time-zone lookups over the in-memory indexes of the other packages.
+//
+//	The message and client types are generated from `geonames.proto` into `geonames.pb.go` and `geonames_grpc.pb.go`
+//	and documented there.
+package geonames_grpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative geonames.proto
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-geo/geonames/admin-divisions"
+	"github.com/go-geo/geonames/countries"
+	"github.com/go-geo/geonames/import-dumps"
+	"github.com/go-geo/geonames/postal-codes"
+	"github.com/go-geo/geonames/reverse-geo"
+	"github.com/go-geo/geonames/search-names"
+	"github.com/go-geo/geonames/time-zones"
+)
+
+var (
+	//	Upper bound of all `limit` request fields
+	MaxLimit = 100
+
+	//	Upper bound of the number of locations per `NearestBatch` call
+	MaxBatch = 10000
+)
+
+//	Implements `GeonamesServer`. All indexes must be set, except `Postals` (for a server without postal codes).
+type Server struct {
+	UnimplementedGeonamesServer
+
+	Admins    *geonames_admin.Resolver
+	Countries *geonames_countries.Table
+	Postals   *geonames_postal.Index
+	Reverse   *geonames_reverse.Index
+	Names     *geonames_search.Index
+	Timezones *geonames_tz.Resolver
+
+	//	Whether `Names.BuildFuzzy` was called, enabling `SearchRequest.Fuzzy`
+	Fuzzy bool
+}
+
+func (me *Server) Nearest(ctx context.Context, req *NearestRequest) (resp *PlacesResponse, err error) {
+	var limit int
+	if limit, err = checkLimit(req.Limit, 1); err == nil {
+		if err = checkLonLat(req.Location); err == nil {
+			resp = &PlacesResponse{Places: me.nearest(req.Location, limit, req.Filter)}
+		}
+	}
+	return
+}
+
+func (me *Server) NearestBatch(req *NearestBatchRequest, stream Geonames_NearestBatchServer) (err error) {
+	var limit int
+	if limit, err = checkLimit(req.Limit, 1); err != nil {
+		return
+	} else if len(req.Locations) > MaxBatch {
+		return status.Errorf(codes.InvalidArgument, "at most %d locations per batch", MaxBatch)
+	}
+	for i, loc := range req.Locations {
+		if err = checkLonLat(loc); err != nil {
+			return status.Errorf(codes.InvalidArgument, "locations[%d]: %s", i, status.Convert(err).Message())
+		}
+	}
+	for i, loc := range req.Locations {
+		if err = stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		if err = stream.Send(&NearestBatchResult{Index: int32(i), Places: me.nearest(loc, limit, req.Filter)}); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (me *Server) Search(ctx context.Context, req *SearchRequest) (resp *PlacesResponse, err error) {
+	var limit int
+	if limit, err = checkLimit(req.Limit, 10); err != nil {
+		return
+	} else if len(req.Query) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	} else if req.Fuzzy && !me.Fuzzy {
+		return nil, status.Error(codes.FailedPrecondition, "fuzzy search is not enabled on this server")
+	}
+	var candidates []geonames_search.Candidate
+	if req.Fuzzy {
+		candidates = me.Names.SearchFuzzy(req.Query, limit, nil)
+	} else {
+		candidates = me.Names.Search(req.Query, limit)
+	}
+	resp = &PlacesResponse{Places: make([]*Place, len(candidates))}
+	for i, c := range candidates {
+		p := me.place(c.Place)
+		p.Score, p.MatchedName = c.Score, c.MatchedName
+		resp.Places[i] = p
+	}
+	return
+}
+
+func (me *Server) Postal(ctx context.Context, req *PostalRequest) (resp *PostalsResponse, err error) {
+	var (
+		limit   int
+		results []geonames_postal.Result
+	)
+	if me.Postals == nil {
+		return nil, status.Error(codes.Unimplemented, "postal codes are not loaded on this server")
+	} else if limit, err = checkLimit(req.Limit, 10); err != nil {
+		return
+	}
+	switch by := req.By.(type) {
+	case *PostalRequest_Code:
+		results = me.Postals.Lookup(req.CountryCode, by.Code)
+	case *PostalRequest_Prefix:
+		results = me.Postals.Prefix(req.CountryCode, by.Prefix, limit)
+	case *PostalRequest_Near:
+		if err = checkLonLat(by.Near); err != nil {
+			return
+		}
+		var filter *geonames_postal.Filter
+		if len(req.CountryCode) > 0 {
+			filter = &geonames_postal.Filter{Countries: []string{req.CountryCode}}
+		}
+		results = me.Postals.Nearest(by.Near.Lon, by.Near.Lat, limit, filter)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "one of code, prefix or near is required")
+	}
+	resp = &PostalsResponse{Postals: make([]*Postal, len(results))}
+	for i, r := range results {
+		p := r.Postal
+		resp.Postals[i] = &Postal{CountryCode: p.CountryCode, Code: p.PostalCode, PlaceName: p.PlaceName, Location: lonLat(p.LonLat),
+			Admins: p.Admins, Accuracy: p.Accuracy, DistanceKm: r.DistanceKm}
+	}
+	return
+}
+
+func (me *Server) Country(ctx context.Context, req *CountryRequest) (*CountryInfo, error) {
+	c := me.Countries.Lookup(req.Code)
+	if c == nil {
+		return nil, status.Errorf(codes.NotFound, "no such country: %#v", req.Code)
+	}
+	return &CountryInfo{Iso2: c.Code.Iso2, Iso3: c.Code.Iso3, IsoNumeric: c.Code.IsoNum, Fips: c.Code.Fips, Name: c.Name, Capital: c.Capital,
+		AreaSqKm: c.AreaSqKm, Population: c.Population, Continent: c.Continent, Tld: c.Tld, CurrencyCode: c.Currency.Code, CurrencyName: c.Currency.Name,
+		CallingCode: c.CallingCode, PostalCodeFormat: c.PostalCode.Format, PostalCodeRegex: c.PostalCode.Regex, Languages: c.Languages,
+		Neighbors: c.Neighbors, GeonameId: c.Id}, nil
+}
+
+func (me *Server) AdminChain(ctx context.Context, req *AdminChainRequest) (*AdminChainResponse, error) {
+	var chain geonames_admin.Chain
+	if req.PlaceId != 0 {
+		p := me.Reverse.Store.Place(req.PlaceId)
+		if p == nil {
+			return nil, status.Errorf(codes.NotFound, "no such place: %d", req.PlaceId)
+		}
+		chain = me.Admins.Place(&p.PlaceRec)
+	} else if len(req.CountryCode) == 0 || len(req.AdminCodes) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "either place_id or country_code and admin_codes are required")
+	} else {
+		chain = me.Admins.Resolve(req.CountryCode, req.AdminCodes...)
+	}
+	return &AdminChainResponse{Divisions: divisions(chain)}, nil
+}
+
+func (me *Server) Timezone(ctx context.Context, req *TimezoneRequest) (*TimezoneResponse, error) {
+	if err := checkLonLat(req.Location); err != nil {
+		return nil, err
+	}
+	at := time.Now()
+	if req.At != nil {
+		at = req.At.AsTime()
+	}
+	r, ok := me.Timezones.At(req.Location.Lon, req.Location.Lat, at)
+	if !ok {
+		return nil, status.Error(codes.NotFound, "no populated place near the location")
+	}
+	resp := &TimezoneResponse{Name: r.Name, OffsetSeconds: int32(r.Offset / time.Second), Abbrev: r.Abbrev, Dst: r.IsDST, DistanceKm: r.DistanceKm}
+	if r.Place != nil {
+		resp.PlaceId = r.Place.Id
+	}
+	if r.Country != nil {
+		resp.CountryCode = r.Country.Code.Iso2
+	}
+	return resp, nil
+}
+
+func (me *Server) nearest(loc *LonLat, limit int, f *PlaceFilter) (places []*Place) {
+	var filter *geonames_reverse.Filter
+	if f != nil {
+		filter = &geonames_reverse.Filter{FeatureClasses: f.FeatureClasses, FeatureCodes: f.FeatureCodes, Countries: f.CountryCodes,
+			MinPopulation: f.MinPopulation, MaxDistanceKm: f.MaxDistanceKm}
+	}
+	results := me.Reverse.Nearest(loc.Lon, loc.Lat, limit, filter)
+	places = make([]*Place, len(results))
+	for i, r := range results {
+		places[i] = me.place(r.Place)
+		places[i].DistanceKm = r.DistanceKm
+	}
+	return
+}
+
+func (me *Server) place(p *geonames_import.Place) *Place {
+	return &Place{Id: p.Id, Name: p.Name, NameAscii: p.NameAscii, Location: lonLat(p.LonLat), FeatureClass: p.Feature.Class, FeatureCode: p.Feature.Code,
+		CountryCode: p.Country.Code, Admins: divisions(me.Admins.Place(&p.PlaceRec)), Population: p.Population, Elevation: p.Elevation, Timezone: p.TimezoneName}
+}
+
+func divisions(chain geonames_admin.Chain) (divs []*AdminDivision) {
+	for _, d := range chain {
+		if d != nil {
+			divs = append(divs, &AdminDivision{Level: int32(d.Level), Code: d.Code, Id: d.Id, Name: d.Name, NameAscii: d.NameAscii})
+		}
+	}
+	return
+}
+
+func lonLat(ll []float64) *LonLat {
+	if len(ll) == 2 {
+		return &LonLat{Lon: ll[0], Lat: ll[1]}
+	}
+	return nil
+}
+
+func checkLimit(limit int32, def int) (int, error) {
+	if limit < 0 || int(limit) > MaxLimit {
+		return 0, status.Errorf(codes.InvalidArgument, "limit must be within 0..%d", MaxLimit)
+	} else if limit == 0 {
+		return def, nil
+	}
+	return int(limit), nil
+}
+
+func checkLonLat(ll *LonLat) error {
+	if ll == nil {
+		return status.Error(codes.InvalidArgument, "location is required")
+	} else if math.IsNaN(ll.Lon) || math.IsNaN(ll.Lat) || math.IsInf(ll.Lon, 0) || math.IsInf(ll.Lat, 0) ||
+		ll.Lon < -180 || ll.Lon > 180 || ll.Lat < -90 || ll.Lat > 90 {
+		return status.Errorf(codes.InvalidArgument, "location out of range: %v, %v", ll.Lon, ll.Lat)
+	}
+	return nil
+}
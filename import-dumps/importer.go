@@ -0,0 +1,240 @@
+//	Cleans up, links and normalizes the records of raw `download.geonames.org/export/dump` files (iterated via `parse-dumps` package) and streams them into any `Sink` (such as a database, file exporter or in-memory store).
+package geonames_import
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-geo/geonames/parse-dumps"
+	"github.com/go-utils/ustr"
+)
+
+//	Collection names passed to `Sink.BeginCollection` and `Sink.EndCollection`, in the order they are produced by `Importer.Run`.
+const (
+	Timezones = "timezones"
+	Features  = "features"
+	Countries = "countries"
+	Admins    = "admins"
+	Postals   = "postals"
+	Places    = "places"
+)
+
+var (
+	//	Approximate record counts per collection, passed to `Sink.BeginCollection`
+	CapHints = map[string]int{
+		Timezones: 420,
+		Features:  700,
+		Countries: 300,
+		Admins:    40000,
+		Postals:   900000,
+		Places:    8520000,
+	}
+)
+
+//	Record counts of one collection during an `Importer.Run`.
+type CollStats struct {
+	//	Number of records read from the source file
+	Read int `bson:"read"`
+
+	//	Number of records passed to `Sink.Write`
+	Written int `bson:"written"`
+
+	//	Number of records deliberately filtered out (such as CEDEX postal codes, or admin codes lacking a country prefix)
+	Skipped int `bson:"skipped"`
+
+	//	Number of records dropped for lack of valid coordinates or a usable name
+	Invalid int `bson:"invalid"`
+
+	//	Number of references to unknown countries, admin divisions, features or time zones (referenced as `0`),
+	//	counting each unknown kind of a record (so a place with both an unknown feature and time zone counts twice)
+	Orphans int `bson:"orphans"`
+}
+
+//	Receives the normalized records produced by an `Importer`.
+type Sink interface {
+	//	Called before the first `Write` of collection `name`. `capHint` approximates its record count.
+	BeginCollection(name string, capHint int) error
+
+	//	Receives the next batch of records of the current collection, as `*Timezone`, `*Feature`, `*Country`, `*Admin`, `*Postal` or `*Place`.
+	//
+	//	The pointers may be retained, but the `recs` slice itself is reused after `Write` returns.
+	Write(recs []interface{}) error
+
+	//	Called after the last `Write` of collection `name`.
+	EndCollection(name string) error
+
+	//	Called once after all collections were written successfully.
+	Finish() error
+}
+
+//	Feeds the records of a `geonames_parse.Iterator` into a `Sink`.
+type Importer struct {
+	//	Receives all records
+	Sink Sink
+
+	//	How many records are at most passed per `Sink.Write` call at once
+	BatchSize int
+
+	//	Whether to `log.Printf` progress
+	Log bool
+
+	//	All-upper-case strings (where applicable) longer than this value are `Title`d (`FOO BAR` becomes `Foo Bar`).
+	//	Set to `0` to disable this.
+	TitleAllUpper int
+
+	//	Whether `Run` fails on the first orphan (see `CollStats.Orphans`) instead of referencing it as `0`,
+	//	without reading or writing any further records
+	Strict bool
+
+	//	Record counts per collection name, reset and filled by `Run`
+	Stats map[string]*CollStats
+
+	//	Start and end time of the last `Run`
+	Started, Finished time.Time
+
+	coll string
+	err  error
+	recs []interface{}
+	stat *CollStats
+
+	mAdmins    map[string]int64
+	mCountries map[string]int
+	mFeatures  map[string]int
+	mTimezones map[string]int
+}
+
+//	Initializes a new `Importer` with the specified `sink` and default settings.
+func NewImporter(sink Sink) (me *Importer) {
+	me = &Importer{Sink: sink, BatchSize: 125000, Log: true, TitleAllUpper: 1}
+	return
+}
+
+//	Writes all records from `geo` into `me.Sink`, in the following order:
+//	Time zones, features, countries, administrative divisions, postal codes, places (geo-names).
+//
+//	Iterates over a copy of `geo` whose `Stop` aborts the iteration on the first failed `Sink.Write` (or orphan, if `Strict`).
+func (me *Importer) Run(geo *geonames_parse.Iterator) (err error) {
+	geoCopy := *geo
+	geo, geoCopy.Stop = &geoCopy, func() error { return me.err }
+	me.mAdmins, me.mCountries, me.mFeatures, me.mTimezones = map[string]int64{}, map[string]int{}, map[string]int{}, map[string]int{}
+	me.Stats, me.Started, me.Finished = map[string]*CollStats{}, time.Now(), time.Time{}
+	if err = me.collection(Timezones, func() error { return geo.Timezones(me.onTimezone) }); err == nil {
+		if err = me.collection(Features, func() error { return geo.Features(me.onFeature) }); err == nil {
+			if err = me.collection(Countries, func() error { return geo.Countries(me.onCountry) }); err == nil {
+				if err = me.collection(Admins, func() error { return geo.AdminAll(me.onAdmin) }); err == nil {
+					if err = me.collection(Postals, func() error { return geo.PostalCodes(me.onPostal) }); err == nil {
+						if err = me.collection(Places, func() error { return geo.Places(me.onPlace) }); err == nil {
+							if err = me.Sink.Finish(); err == nil {
+								me.Finished = time.Now()
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+func (me *Importer) add(rec interface{}) {
+	if me.err == nil {
+		if me.recs = append(me.recs, rec); len(me.recs) >= me.BatchSize && me.coll != Countries {
+			me.err = me.flush()
+		}
+	}
+}
+
+func (me *Importer) collection(name string, iterate func() error) (err error) {
+	if me.Log {
+		log.Printf("Writing %#v..", name)
+	}
+	me.coll, me.err, me.recs, me.stat = name, nil, me.recs[:0], &CollStats{}
+	me.Stats[name] = me.stat
+	if err = me.Sink.BeginCollection(name, CapHints[name]); err == nil {
+		if err = iterate(); err == nil && name == Countries {
+			me.prepCountries()
+		}
+		if err == nil {
+			if err = me.err; err == nil {
+				if err = me.flush(); err == nil {
+					if err = me.Sink.EndCollection(name); me.Log && err == nil {
+						log.Print("\tall done.")
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+func (me *Importer) flush() (err error) {
+	var max int
+	for i := 0; i < len(me.recs) && err == nil; i += me.BatchSize {
+		if max = i + me.BatchSize; max >= len(me.recs) {
+			max = len(me.recs)
+		}
+		if err = me.Sink.Write(me.recs[i:max]); err == nil {
+			if me.stat.Written += max - i; me.Log {
+				log.Printf("\t%v done..", me.stat.Written)
+			}
+		}
+	}
+	me.recs = me.recs[:0]
+	return
+}
+
+//	Counts an orphan reference (or in `Strict` mode, fails with its description unless already failed).
+func (me *Importer) orphan(index int, format string, args ...interface{}) {
+	if me.stat.Orphans++; me.Strict && me.err == nil {
+		me.err = fmt.Errorf("%s record %d: "+format, append([]interface{}{me.coll, index}, args...)...)
+	}
+}
+
+func (me *Importer) placeName(n string) string {
+	if len(n) > 4 && ustr.IsUpperAscii(n) {
+		n = me.title(n)
+	}
+	if p1 := strings.Index(n, "["); p1 > 0 {
+		if p2 := strings.LastIndex(n, "]"); p2 > p1 && ustr.Has(n[p1:p2], " ") {
+			n = ustr.ReduceSpaces(ustr.Concat(n[:p1], n[p2+1:]))
+		}
+	}
+	if IsPlaceholderName(n) {
+		n = ""
+	}
+	return n
+}
+
+//	Returns whether `n` is a placeholder such as `name unknown` or `name to be determined`, which `Importer.Run` discards.
+func IsPlaceholderName(n string) bool {
+	return ustr.HasAny(strings.ToLower(n), "name not found", "name to be determined", "name not shown", "name_unknown", "name unknown", "name not known")
+}
+
+func (me *Importer) prepCountries() {
+	var c *Country
+	for i := 0; i < len(me.recs) && me.err == nil; i++ {
+		c = me.recs[i].(*Country)
+		c.NeighborRefs = make([]int, 0, len(c.Neighbors))
+		var unknown []string
+		for _, cnc := range c.Neighbors {
+			if c.NeighborRefs = append(c.NeighborRefs, me.mCountries[cnc]); me.mCountries[cnc] == 0 {
+				unknown = append(unknown, cnc)
+			}
+		}
+		if len(unknown) > 0 {
+			me.orphan(c.Ref-1, "unknown neighbor countries %v of %#v", unknown, c.Code.Iso2)
+		}
+	}
+}
+
+func (me *Importer) title(str string) string {
+	if me.TitleAllUpper > 0 && len(str) > me.TitleAllUpper {
+		if ustr.IsUpper(str) {
+			str = strings.ToLower(str)
+		}
+		str = strings.Title(str)
+	}
+	return str
+}
@@ -0,0 +1,105 @@
+package geonames_import
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-geo/geonames/parse-dumps"
+	"github.com/go-utils/uslice"
+	"github.com/go-utils/ustr"
+)
+
+func (me *Importer) onAdmin(i int, r *geonames_parse.AdminRec) {
+	me.stat.Read++
+	if concat := ustr.Split(r.Code, "."); len(concat) > 1 {
+		me.mAdmins[r.Code] = r.Id
+		a := &Admin{AdminRec: *r, SubCode: strings.Join(concat[1:], "."), CountryRef: me.mCountries[concat[0]]}
+		if a.CountryRef == 0 {
+			me.orphan(i, "unknown country %#v of admin %#v", concat[0], r.Code)
+		}
+		me.add(a)
+	} else {
+		me.stat.Skipped++
+	}
+}
+
+func (me *Importer) onCountry(i int, r *geonames_parse.CountryRec) {
+	me.stat.Read++
+	me.mCountries[r.Code.Iso2] = i + 1
+	me.add(&Country{CountryRec: *r, Ref: i + 1})
+}
+
+func (me *Importer) onFeature(i int, r *geonames_parse.FeatureRec) {
+	me.stat.Read++
+	me.mFeatures[r.Code] = i + 1
+	me.add(&Feature{FeatureRec: *r, Ref: i + 1})
+}
+
+func (me *Importer) onPlace(i int, r *geonames_parse.PlaceRec) {
+	me.stat.Read++
+	if r.Name, r.NameAscii = me.placeName(r.Name), me.placeName(r.NameAscii); len(r.Name) == 0 {
+		r.Name = r.NameAscii
+	}
+	if r.Name == r.NameAscii {
+		r.NameAscii = ""
+	}
+	if r.NamesAlt = uslice.StrEach(r.NamesAlt, me.placeName); len(r.Name) == 0 {
+		r.Name = ustr.FirstNonEmpty(r.NamesAlt...)
+	}
+	r.NamesAlt = uslice.StrWithout(r.NamesAlt, true, r.Name, r.NameAscii)
+
+	if len(r.Name) == 0 || len(r.LonLat) != 2 {
+		me.stat.Invalid++
+		return
+	}
+
+	p := &Place{PlaceRec: *r, CountryRef: me.mCountries[r.Country.Code], TimezoneRef: me.mTimezones[r.TimezoneName],
+		FeatureRef: me.mFeatures[fmt.Sprintf("%s.%s", r.Feature.Class, r.Feature.Code)],
+	}
+	if p.AdminRef = me.mAdmins[fmt.Sprintf("%s.%s.%s", r.Country.Code, r.Admin.Code1, r.Admin.Code2)]; p.AdminRef == 0 {
+		p.AdminRef = me.mAdmins[fmt.Sprintf("%s.%s", r.Country.Code, r.Admin.Code1)] // more-general only if more-specific wasnt found
+	}
+	if p.CountryRef == 0 && len(r.Country.Code) > 0 {
+		me.orphan(i, "unknown country %#v of place %d", r.Country.Code, r.Id)
+	}
+	if p.AdminRef == 0 && len(r.Admin.Code1) > 0 && r.Admin.Code1 != "00" {
+		me.orphan(i, "unknown admin %#v of place %d", r.Country.Code+"."+r.Admin.Code1, r.Id)
+	}
+	if p.FeatureRef == 0 && len(r.Feature.Class) > 0 {
+		me.orphan(i, "unknown feature %#v of place %d", r.Feature.Class+"."+r.Feature.Code, r.Id)
+	}
+	if p.TimezoneRef == 0 && len(r.TimezoneName) > 0 {
+		me.orphan(i, "unknown time zone %#v of place %d", r.TimezoneName, r.Id)
+	}
+	me.add(p)
+}
+
+func (me *Importer) onPostal(i int, r *geonames_parse.PostalRec) {
+	if me.stat.Read++; len(r.LonLat) != 2 {
+		me.stat.Invalid++
+		return
+	} else if ustr.HasAnyCase(r.PostalCode, "CEDEX") {
+		me.stat.Skipped++
+		return
+	}
+	p := &Postal{PostalRec: *r, Ref: i + 1, CountryRef: me.mCountries[r.CountryCode]}
+	if p.CountryRef == 0 {
+		me.orphan(i, "unknown country %#v of postal code %#v", r.CountryCode, r.PostalCode)
+	}
+	p.PlaceName = me.title(r.PlaceName)
+	p.Admins = map[string]string{r.Admin.Code1: r.Admin.Name1, r.Admin.Code2: r.Admin.Name2, r.Admin.Code3: r.Admin.Name3}
+	for k, v := range p.Admins {
+		if len(k) == 0 || len(v) == 0 {
+			delete(p.Admins, k)
+		} else {
+			p.Admins[k] = me.title(v)
+		}
+	}
+	me.add(p)
+}
+
+func (me *Importer) onTimezone(i int, r *geonames_parse.TimezoneRec) {
+	me.stat.Read++
+	me.mTimezones[r.TimezoneName] = i + 1
+	me.add(&Timezone{TimezoneRec: *r, Ref: i + 1, Name: strings.Replace(r.TimezoneName, "_", " ", -1)})
+}
@@ -0,0 +1,71 @@
+package geonames_import
+
+import (
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+//	Normalized `geonames_parse.AdminRec`
+type Admin struct {
+	geonames_parse.AdminRec
+
+	//	`Code` without its leading country-code segment (eg. `CA.037` for `US.CA.037`)
+	SubCode string
+
+	//	`Country.Ref` of the country referred to by the first `Code` segment, or `0` if unknown
+	CountryRef int
+}
+
+//	Normalized `geonames_parse.CountryRec`
+type Country struct {
+	geonames_parse.CountryRec
+
+	//	1-based record number, referred to by all other records' `CountryRef`s
+	Ref int
+
+	//	`Ref`s of all `Neighbors`, or `0` for each unknown one
+	NeighborRefs []int
+}
+
+//	Normalized `geonames_parse.FeatureRec`
+type Feature struct {
+	geonames_parse.FeatureRec
+
+	//	1-based record number, referred to by `Place.FeatureRef`
+	Ref int
+}
+
+//	Normalized `geonames_parse.PlaceRec`, with cleaned-up `Name`, `NameAscii` and `NamesAlt`
+type Place struct {
+	geonames_parse.PlaceRec
+
+	//	The `Admin.Id` of the most-specific known administrative division (`Admin2` if known, else `Admin1`), or `0`
+	AdminRef int64
+
+	//	References to the respective records, or `0` if unknown
+	CountryRef, FeatureRef, TimezoneRef int
+}
+
+//	Normalized `geonames_parse.PostalRec`, with `Title`d `PlaceName`
+type Postal struct {
+	geonames_parse.PostalRec
+
+	//	1-based line number in the postal-codes file
+	Ref int
+
+	//	`Country.Ref` of `CountryCode`, or `0` if unknown
+	CountryRef int
+
+	//	Maps all non-empty admin codes to their non-empty (`Title`d) names
+	Admins map[string]string
+}
+
+//	Normalized `geonames_parse.TimezoneRec`
+type Timezone struct {
+	geonames_parse.TimezoneRec
+
+	//	1-based record number, referred to by `Place.TimezoneRef`
+	Ref int
+
+	//	`TimezoneName` with underscores replaced by spaces
+	Name string
+}
@@ -0,0 +1,188 @@
+package geonames_makedb
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-geo/geonames/import-dumps"
+	"github.com/go-geo/geonames/parse-dumps"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+//	Writes all records from `geo` into `dirPath/dbName` (in the same order as `Insert`) without connecting to any MongoDB server.
+//
+//	If `jsonLines`, each collection is written as a `.json` file of canonical extended-JSON documents (one per line, for `mongoimport`),
+//	otherwise as a `.bson` file (for `mongorestore --dir=dirPath`). Either way, a `.metadata.json` file records the collection's indexes.
+//	Finally, an `ImportMeta` document is written to `CollImportsName` the same way.
+//
+//	If the import fails (such as on the first orphan if `Strict`), the files written so far are removed again.
+func Dump(geo *geonames_parse.Iterator, dirPath, dbName string, jsonLines bool) (err error) {
+	var colls compiledSchema
+	if colls, err = UseSchema.compile(); err == nil {
+		sink := NewDumpSink(dirPath, dbName, jsonLines)
+		sink.colls = colls
+		imp := geonames_import.NewImporter(sink)
+		imp.BatchSize, imp.Log, imp.TitleAllUpper, imp.Strict = BatchSize, Log, TitleAllUpper, Strict
+		if err = imp.Run(geo); err == nil {
+			var meta *ImportMeta
+			if meta, err = newImportMeta(geo, imp); err == nil {
+				if err = sink.open(CollImportsName); err == nil {
+					if err = sink.writeDoc(meta); err == nil {
+						err = sink.close(CollImportsName, nil)
+					}
+				}
+			}
+		} else {
+			sink.remove()
+		}
+	}
+	return
+}
+
+//	A `geonames_import.Sink` that writes all records into `mongorestore`- or `mongoimport`-compatible files.
+type DumpSink struct {
+	//	Output directory, collection files are written into its `DbName` sub-directory
+	DirPath string
+
+	//	Database name
+	DbName string
+
+	//	Whether to write extended-JSON lines (`.json`) instead of `.bson` files
+	JsonLines bool
+
+	buf     *bufio.Writer
+	file    *os.File
+	name    string
+	colls   compiledSchema
+	written []string
+}
+
+//	Initializes a new `DumpSink` with the specified settings.
+func NewDumpSink(dirPath, dbName string, jsonLines bool) (me *DumpSink) {
+	me = &DumpSink{DirPath: dirPath, DbName: dbName, JsonLines: jsonLines}
+	return
+}
+
+//	Implements `geonames_import.Sink` interface.
+func (me *DumpSink) BeginCollection(name string, capHint int) (err error) {
+	if me.colls == nil {
+		me.colls, err = UseSchema.compile()
+	}
+	if err == nil {
+		me.name = name
+		err = me.open(me.colls.name(name))
+	}
+	return
+}
+
+//	Implements `geonames_import.Sink` interface.
+func (me *DumpSink) EndCollection(name string) (err error) {
+	err = me.close(me.colls.name(name), me.colls[name].indexes)
+	return
+}
+
+//	Implements `geonames_import.Sink` interface.
+func (me *DumpSink) Finish() (err error) {
+	return
+}
+
+//	Implements `geonames_import.Sink` interface.
+func (me *DumpSink) Write(recs []interface{}) (err error) {
+	for _, r := range recs {
+		if err = me.writeDoc(doc(me.colls[me.name].paths, r)); err != nil {
+			break
+		}
+	}
+	return
+}
+
+func (me *DumpSink) close(mongoName string, keys []bson.D) (err error) {
+	if me.file != nil {
+		if err = me.buf.Flush(); err == nil {
+			err = me.file.Close()
+		} else {
+			me.file.Close()
+		}
+		me.file, me.buf = nil, nil
+	}
+	if err == nil {
+		err = me.writeMetadata(mongoName, keys)
+	}
+	return
+}
+
+func (me *DumpSink) filePath(fileName, ext string) string {
+	return filepath.Join(me.DirPath, me.DbName, fileName+ext)
+}
+
+func (me *DumpSink) open(mongoName string) (err error) {
+	ext := ".bson"
+	if me.JsonLines {
+		ext = ".json"
+	}
+	if err = os.MkdirAll(filepath.Join(me.DirPath, me.DbName), os.ModePerm); err == nil {
+		if me.file, err = os.Create(me.filePath(mongoName, ext)); err == nil {
+			me.buf, me.written = bufio.NewWriterSize(me.file, 1024*1024), append(me.written, me.file.Name())
+		}
+	}
+	return
+}
+
+func (me *DumpSink) writeDoc(doc interface{}) (err error) {
+	var raw []byte
+	if me.JsonLines {
+		if raw, err = bson.MarshalExtJSON(doc, true, false); err == nil {
+			raw = append(raw, '\n')
+		}
+	} else {
+		raw, err = bson.Marshal(doc)
+	}
+	if err == nil {
+		_, err = me.buf.Write(raw)
+	}
+	return
+}
+
+func (me *DumpSink) writeMetadata(mongoName string, keys []bson.D) (err error) {
+	indexes := bson.A{bson.D{{Key: "v", Value: 2}, {Key: "key", Value: bson.D{{Key: "_id", Value: 1}}}, {Key: "name", Value: "_id_"}}}
+	if CreateIndexes {
+		for _, k := range keys {
+			indexes = append(indexes, bson.D{{Key: "v", Value: 2}, {Key: "key", Value: k}, {Key: "name", Value: indexName(k)}})
+		}
+	}
+	var raw []byte
+	if raw, err = bson.MarshalExtJSON(bson.D{
+		{Key: "options", Value: bson.D{}}, {Key: "indexes", Value: indexes},
+		{Key: "collectionName", Value: mongoName}, {Key: "type", Value: "collection"},
+	}, true, false); err == nil {
+		filePath := me.filePath(mongoName, ".metadata.json")
+		if err = os.WriteFile(filePath, raw, 0644); err == nil {
+			me.written = append(me.written, filePath)
+		}
+	}
+	return
+}
+
+//	Closes the current file, if any, and removes all files written so far.
+func (me *DumpSink) remove() {
+	if me.file != nil {
+		me.file.Close()
+		me.file, me.buf = nil, nil
+	}
+	for _, filePath := range me.written {
+		os.Remove(filePath)
+	}
+	me.written = nil
+}
+
+//	The name MongoDB would give by default to an index with the specified `keys` (eg. `c_1_z_1`).
+func indexName(keys bson.D) string {
+	parts := make([]string, 0, 2*len(keys))
+	for _, k := range keys {
+		parts = append(parts, k.Key, fmt.Sprint(k.Value))
+	}
+	return strings.Join(parts, "_")
+}
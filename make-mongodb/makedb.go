@@ -1,17 +1,22 @@
 package geonames_makedb
 
 import (
+	"context"
+	"fmt"
 	"log"
-	"strings"
+	"reflect"
+	"time"
 
-	"github.com/go-forks/mgo"
-	"github.com/go-forks/mgo/bson"
+	"github.com/go-geo/geonames/import-dumps"
 	"github.com/go-geo/geonames/parse-dumps"
-	"github.com/metaleap/go-util/str"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
 )
 
 var (
-	//	How many records are at most passed per `mgo.Collection.Insert` call at once
+	//	How many records are at most passed per `mongo.Collection.BulkWrite` call at once
 	BatchSize = 125000
 
 	//	Whether to `log.Printf` progress
@@ -21,103 +26,157 @@ var (
 	//	Set to `0` to disable this.
 	TitleAllUpper = 1
 
-	recs []interface{}
+	//	Whether imports fail on records referring to unknown countries, admin divisions, features or time zones (see `geonames_import.Importer.Strict`).
+	//	`Sink` then also refuses to write into collections that already exist, so that a failed import can drop all it wrote.
+	Strict = false
+
+	//	Maximum duration of each `mongo.Collection.BulkWrite` call. Set to `0` to disable this.
+	WriteTimeout = 10 * time.Minute
+
+	//	Write concern for all inserts. If `nil`, the `mongo.Database`'s own one applies.
+	WriteConcern = writeconcern.W1()
+
+	//	Whether `Sink` creates (and `DumpSink` records) the secondary indexes of each collection.
+	CreateIndexes = true
 )
 
 //	Inserts all records from `geo` into the specified `db`, in the following order:
 //	Time zones, features, countries, administrative divisions, postal codes, places (geo-names).
-func Insert(geo *geonames_parse.Iterator, db *mgo.Database) (err error) {
-	recs = make([]interface{}, 0, CollTimezonesCap)
-	if err = insert(geo, db, CollTimezonesName, CollFeaturesCap, geo.Timezones(onTimezone)); err == nil {
-		if err = insert(geo, db, CollFeaturesName, CollCountriesCap, geo.Features(onFeature)); err == nil {
-			if err = insert(geo, db, CollCountriesName, CollAdminsCap, geo.Countries(onCountry)); err == nil {
-				if err = insert(geo, db, CollAdminsName, CollPostalsCap, geo.AdminAll(onAdmin)); err == nil {
-					if err = insert(geo, db, CollPostalsName, CollPlacesCap, geo.PostalCodes(onPostal)); err == nil {
-						if err = insert(geo, db, CollPlacesName, 0, geo.Places(onPlace)); err == nil {
-						}
-					}
-				}
+//	Finally, an `ImportMeta` document describing the import is inserted into `CollImportsName`.
+func Insert(geo *geonames_parse.Iterator, db *mongo.Database) (err error) {
+	return InsertContext(context.Background(), geo, db)
+}
+
+//	Like `Insert`, but aborts once `ctx` is done.
+//
+//	If the import fails (such as on the first orphan if `Strict`), the collections it created are dropped again. Collections that
+//	existed before keep the documents written into them so far, unless `Strict` (which requires all collections not to exist yet).
+func InsertContext(ctx context.Context, geo *geonames_parse.Iterator, db *mongo.Database) (err error) {
+	var colls compiledSchema
+	if colls, err = UseSchema.compile(); err == nil {
+		sink := NewSink(db)
+		sink.Ctx, sink.colls = ctx, colls
+		imp := geonames_import.NewImporter(sink)
+		imp.BatchSize, imp.Log, imp.TitleAllUpper, imp.Strict = BatchSize, Log, TitleAllUpper, Strict
+		if err = imp.Run(geo); err == nil {
+			var meta *ImportMeta
+			if meta, err = newImportMeta(geo, imp); err == nil {
+				_, err = db.Collection(CollImportsName).InsertOne(ctx, meta)
 			}
+		} else {
+			sink.drop()
 		}
 	}
 	return
 }
 
-func insert(geo *geonames_parse.Iterator, db *mgo.Database, collName string, nextRecsCap int, err error) error {
-	switch collName {
-	case CollCountriesName:
-		prepCountries()
+//	A `geonames_import.Sink` that inserts all records into a MongoDB database via unordered `BulkWrite`s.
+type Sink struct {
+	//	Receives all inserts
+	Db *mongo.Database
+
+	//	Used for all database operations, defaults to `context.Background()`
+	Ctx context.Context
+
+	coll    *mongo.Collection
+	name    string
+	models  []mongo.WriteModel
+	colls   compiledSchema
+	created []*mongo.Collection
+}
+
+//	Initializes a new `Sink` for the specified `db`.
+func NewSink(db *mongo.Database) (me *Sink) {
+	me = &Sink{Db: db, Ctx: context.Background()}
+	return
+}
+
+//	Implements `geonames_import.Sink` interface.
+func (me *Sink) BeginCollection(name string, capHint int) (err error) {
+	if me.colls == nil {
+		me.colls, err = UseSchema.compile()
 	}
-	if err == nil && len(recs) > 0 {
-		if Log {
-			log.Printf("Insert %v %#v..", len(recs), collName)
+	if err == nil {
+		opts := options.Collection()
+		if WriteConcern != nil {
+			opts.SetWriteConcern(WriteConcern)
 		}
-		var max int
-		for i := 0; i < len(recs); i += BatchSize {
-			if max = i + BatchSize; max >= len(recs) {
-				max = len(recs)
-			}
-			if Log && i > 0 {
-				log.Printf("\t%v done..", i)
-			}
-			if err = db.C(collName).Insert(recs[i:max]...); err != nil {
-				break
+		me.coll, me.name = me.Db.Collection(me.colls.name(name), opts), name
+		var existing []string
+		if existing, err = me.Db.ListCollectionNames(me.Ctx, bson.D{{Key: "name", Value: me.coll.Name()}}); err == nil {
+			if len(existing) == 0 {
+				me.created = append(me.created, me.coll)
+			} else if Strict {
+				err = fmt.Errorf("collection %s already exists: drop it first, or import without Strict", me.coll.Name())
 			}
 		}
-		if Log && err == nil {
-			log.Print("\tall done.")
-		}
-		if nextRecsCap > 0 {
-			if Log {
-				log.Printf("Loading <%v records..", nextRecsCap)
-			}
-			recs = make([]interface{}, 0, nextRecsCap)
-		}
 	}
-	return err
+	return
 }
 
-func placeName(n string) string {
-	if len(n) > 4 && ustr.IsUpperAscii(n) {
-		n = title(n)
-	}
-	if p1 := strings.Index(n, "["); p1 > 0 {
-		if p2 := strings.LastIndex(n, "]"); p2 > p1 && ustr.Has(n[p1:p2], " ") {
-			n = ustr.ReduceSpaces(ustr.Concat(n[:p1], n[p2+1:]))
+//	Drops all collections that did not exist before this `Sink` began writing them.
+func (me *Sink) drop() {
+	for _, coll := range me.created {
+		if err := coll.Drop(context.Background()); err != nil && Log {
+			log.Printf("	dropping %s: %v", coll.Name(), err)
 		}
 	}
-	if ustr.HasAny(strings.ToLower(n), "name not found", "name to be determined", "name not shown", "name_unknown", "name unknown", "name not known") {
-		n = ""
+	me.created = nil
+}
+
+//	Implements `geonames_import.Sink` interface.
+func (me *Sink) EndCollection(name string) (err error) {
+	if keys := me.colls[name].indexes; CreateIndexes && len(keys) > 0 {
+		models := make([]mongo.IndexModel, 0, len(keys))
+		for _, k := range keys {
+			models = append(models, mongo.IndexModel{Keys: k})
+		}
+		if Log {
+			log.Printf("\tindexing %v..", len(models))
+		}
+		_, err = me.coll.Indexes().CreateMany(me.Ctx, models)
 	}
-	return n
+	me.coll, me.models = nil, nil
+	return
+}
+
+//	Implements `geonames_import.Sink` interface.
+func (me *Sink) Finish() (err error) {
+	return
 }
 
-func prepCountries() {
-	var (
-		m    bson.M
-		cn   []string
-		ok   bool
-		cnc  string
-		refs []int
-	)
+//	Implements `geonames_import.Sink` interface.
+func (me *Sink) Write(recs []interface{}) (err error) {
+	me.models = me.models[:0]
 	for _, r := range recs {
-		m = r.(bson.M)
-		if cn, ok = m[CollCountriesField_NeighborCountries].([]string); ok {
-			refs = make([]int, 0, len(cn))
-			for _, cnc = range cn {
-				refs = append(refs, mCountries[cnc])
-			}
-			m[CollCountriesField_NeighborCountries] = refs
-		}
+		me.models = append(me.models, mongo.NewInsertOneModel().SetDocument(doc(me.colls[me.name].paths, r)))
 	}
+	if len(me.models) > 0 {
+		err = me.bulkWrite(me.models)
+	}
+	return
+}
+
+func (me *Sink) bulkWrite(models []mongo.WriteModel) (err error) {
+	ctx := me.Ctx
+	if WriteTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, WriteTimeout)
+		defer cancel()
+	}
+	_, err = me.coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return
 }
 
-func title(str string) string {
-	if TitleAllUpper > 0 && len(str) > TitleAllUpper {
-		if ustr.IsUpper(str) {
-			str = strings.ToLower(str)
+//	Removes all entries with empty keys or zero-valued / empty values.
+func sparse(m bson.M) bson.M {
+	var rv reflect.Value
+	for k, v := range m {
+		if len(k) == 0 || v == nil {
+			delete(m, k)
+		} else if rv = reflect.ValueOf(v); rv.IsZero() || ((rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map) && rv.Len() == 0) {
+			delete(m, k)
 		}
-		str = strings.Title(str)
 	}
-	return str
+	return m
 }
@@ -0,0 +1,104 @@
+package geonames_makedb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-geo/geonames/import-dumps"
+	"github.com/go-geo/geonames/parse-dumps"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+//	Connects to `$GEONAMES_TEST_MONGODB` (default `mongodb://localhost:27017`), skipping the test if no `mongod` is reachable.
+func testClient(t *testing.T) *mongo.Client {
+	uri := os.Getenv("GEONAMES_TEST_MONGODB")
+	if len(uri) == 0 {
+		uri = "mongodb://localhost:27017"
+	}
+	client, err := mongo.Connect(options.Client().ApplyURI(uri).SetServerSelectionTimeout(2 * time.Second))
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err = client.Ping(ctx, nil); err != nil {
+			client.Disconnect(context.Background())
+		}
+	}
+	if err != nil {
+		t.Skipf("no mongod reachable at %s: %v", uri, err)
+	}
+	return client
+}
+
+func TestInsertContext(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+	defer client.Disconnect(ctx)
+	db := client.Database(fmt.Sprintf("geonames_test_%d", time.Now().UnixNano()))
+	defer db.Drop(ctx)
+
+	Log = false
+	if err := InsertContext(ctx, geonames_parse.NewIterator("testdata"), db); err != nil {
+		t.Fatal(err)
+	}
+	colls, err := UseSchema.compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta, err := LastImport(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	} else if meta == nil {
+		t.Fatal("no import metadata written")
+	}
+	for _, name := range []string{geonames_import.Timezones, geonames_import.Features, geonames_import.Countries,
+		geonames_import.Admins, geonames_import.Postals, geonames_import.Places} {
+		coll := db.Collection(colls.name(name))
+		count, err := coll.CountDocuments(ctx, bson.D{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if written := meta.Counts[name].Written; written == 0 || count != int64(written) {
+			t.Errorf("%s: %d documents, %d written", name, count, written)
+		}
+		specs, err := coll.Indexes().ListSpecifications(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := len(colls[name].indexes) + 1; len(specs) != expected { // plus the `_id` index
+			t.Errorf("%s: %d indexes, expected %d", name, len(specs), expected)
+		}
+	}
+}
+
+func TestInsertStrictExisting(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+	defer client.Disconnect(ctx)
+	db := client.Database(fmt.Sprintf("geonames_test_%d", time.Now().UnixNano()))
+	defer db.Drop(ctx)
+	colls, err := UseSchema.compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	places := db.Collection(colls.name(geonames_import.Places))
+	if _, err = places.InsertOne(ctx, bson.D{{Key: "_id", Value: 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	Log, Strict = false, true
+	defer func() { Strict = false }()
+	if err = InsertContext(ctx, geonames_parse.NewIterator("testdata"), db); err == nil {
+		t.Fatal("expected an error for the existing places collection")
+	}
+	if count, err := places.CountDocuments(ctx, bson.D{}); err != nil || count != 1 {
+		t.Errorf("places: %d documents (%v), expected the 1 existing one", count, err)
+	}
+	if names, err := db.ListCollectionNames(ctx, bson.D{}); err != nil || len(names) != 1 {
+		t.Errorf("collections %v (%v), expected only the existing one", names, err)
+	}
+}
@@ -0,0 +1,100 @@
+package geonames_makedb
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-geo/geonames/fetch-dumps"
+	"github.com/go-geo/geonames/import-dumps"
+	"github.com/go-geo/geonames/parse-dumps"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+//	Version of this package's import logic, recorded in each `ImportMeta`.
+const Version = "2.0.0"
+
+var (
+	//	Name of the collection receiving one `ImportMeta` document per `Insert` or `Dump`
+	CollImportsName = "_imports"
+
+	//	Whether `ImportMeta.Files` include SHA-256 checksums (which requires reading all source files once more)
+	MetaChecksums = true
+
+	//	Recorded as `ImportMeta.Profile`: the name of the `UseSchema` in use, such as `compact`, `verbose` or the path of its JSON file
+	SchemaProfile = "compact"
+)
+
+//	The settings that filtered or altered records during one `Insert` or `Dump`, in addition to the fixed rules of `geonames_import.Importer`
+//	(whose effects are counted in `ImportMeta.Counts`).
+type ImportFilters struct {
+	//	The `TitleAllUpper` setting
+	TitleAllUpper int `bson:"titleAllUpper"`
+
+	//	The `Strict` setting
+	Strict bool `bson:"strict"`
+
+	//	The `CreateIndexes` setting
+	Indexes bool `bson:"indexes"`
+}
+
+//	Describes one `Insert` or `Dump` run.
+type ImportMeta struct {
+	Id bson.ObjectID `bson:"_id"`
+
+	//	The `Version` of this package that produced the data
+	Version string `bson:"version"`
+
+	//	`UseSchema` and `SchemaProfile` at the time of import
+	Schema  Schema `bson:"schema"`
+	Profile string `bson:"profile"`
+
+	//	Start and end time of the import
+	Started  time.Time `bson:"started"`
+	Finished time.Time `bson:"finished"`
+
+	//	The most recent `LastModified` time of all `Files`, that is, when download.geonames.org last published any of them.
+	//	Zero if none of the `Files` were fetched via `geonames_fetch.FetchFile` (or all were modified since).
+	DumpDate time.Time `bson:"dumpDate,omitempty"`
+
+	//	The `geonames_parse.Iterator.DirPath` of the import
+	DirPath string `bson:"dirPath"`
+
+	//	All source files present in `DirPath`
+	Files []geonames_fetch.LocalFile `bson:"files"`
+
+	//	Record counts per `geonames_import` collection name
+	Counts map[string]geonames_import.CollStats `bson:"counts"`
+
+	//	The settings applied to the records
+	Filters ImportFilters `bson:"filters"`
+}
+
+func newImportMeta(geo *geonames_parse.Iterator, imp *geonames_import.Importer) (me *ImportMeta, err error) {
+	me = &ImportMeta{Id: bson.NewObjectID(), Version: Version, Schema: UseSchema, Profile: SchemaProfile, Started: imp.Started, Finished: imp.Finished,
+		DirPath: geo.DirPath, Counts: map[string]geonames_import.CollStats{},
+		Filters: ImportFilters{TitleAllUpper: imp.TitleAllUpper, Strict: imp.Strict, Indexes: CreateIndexes}}
+	for name, stats := range imp.Stats {
+		me.Counts[name] = *stats
+	}
+	if me.Files, err = geonames_fetch.LocalFiles(geo.DirPath, MetaChecksums); err == nil {
+		for _, lf := range me.Files {
+			if lf.LastModified.After(me.DumpDate) {
+				me.DumpDate = lf.LastModified
+			}
+		}
+	}
+	return
+}
+
+//	Returns the most recently finished `ImportMeta` in `db`, or `nil` if there is none.
+func LastImport(ctx context.Context, db *mongo.Database) (me *ImportMeta, err error) {
+	var meta ImportMeta
+	if err = db.Collection(CollImportsName).FindOne(ctx, bson.D{}, options.FindOne().SetSort(bson.D{{Key: "finished", Value: -1}})).Decode(&meta); err == nil {
+		me = &meta
+	} else if err == mongo.ErrNoDocuments {
+		err = nil
+	}
+	return
+}
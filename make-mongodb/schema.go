@@ -0,0 +1,161 @@
+package geonames_makedb
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/go-geo/geonames/import-dumps"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var (
+	//	The document layout used by `Insert` and `Dump`, validated by them before any records are written
+	UseSchema = SchemaCompact()
+
+	recTypes = map[string]reflect.Type{
+		geonames_import.Admins:    reflect.TypeOf(geonames_import.Admin{}),
+		geonames_import.Countries: reflect.TypeOf(geonames_import.Country{}),
+		geonames_import.Features:  reflect.TypeOf(geonames_import.Feature{}),
+		geonames_import.Places:    reflect.TypeOf(geonames_import.Place{}),
+		geonames_import.Postals:   reflect.TypeOf(geonames_import.Postal{}),
+		geonames_import.Timezones: reflect.TypeOf(geonames_import.Timezone{}),
+	}
+)
+
+//	Describes the MongoDB documents of one `geonames_import` collection.
+type CollSchema struct {
+	//	MongoDB collection name
+	Name string `json:"name"`
+
+	//	Maps document field names to dot-separated field paths into the collection's `geonames_import` record type,
+	//	for example `"c2": "Admin.Code2"` or `"cc": "Country.CodesAlt"` for `geonames_import.Place`.
+	//	Zero-valued and empty fields are omitted from the documents.
+	Fields map[string]string `json:"fields"`
+
+	//	Secondary indexes, each listing one or more document field names in key order.
+	//	A field name may be suffixed with `:-1` for a descending or with `:2dsphere` for a geospatial key.
+	Indexes [][]string `json:"indexes"`
+}
+
+//	Maps `geonames_import` collection names to their document layout.
+type Schema map[string]*CollSchema
+
+//	Maps the document field names of one collection to the `reflect` field index of their value in the collection's record type.
+type fieldPaths map[string][]int
+
+//	The layout of one collection as resolved by `Schema.compile`, fixed for the duration of one import.
+type compiledColl struct {
+	name    string
+	paths   fieldPaths
+	indexes []bson.D
+}
+
+//	Maps `geonames_import` collection names to their `compiledColl`.
+type compiledSchema map[string]*compiledColl
+
+//	Returns the MongoDB collection name of the specified `geonames_import` collection.
+func (me compiledSchema) name(coll string) string {
+	if cc := me[coll]; cc != nil {
+		return cc.name
+	}
+	return coll
+}
+
+//	Loads a `Schema` from the specified JSON file and `Validate`s it.
+func LoadSchema(filePath string) (me Schema, err error) {
+	var raw []byte
+	if raw, err = os.ReadFile(filePath); err == nil {
+		if err = json.Unmarshal(raw, &me); err == nil {
+			err = me.Validate()
+		}
+	}
+	return
+}
+
+//	Returns an error if any collection is missing, unnamed or named non-uniquely, lacks an `_id` field,
+//	or if any field path or index key does not resolve.
+func (me Schema) Validate() (err error) {
+	_, err = me.compile()
+	return
+}
+
+//	Validates `me` and resolves the names, field paths and index keys of all its collections.
+//	Leaves `me` unmodified, as it may be shared by concurrent imports (or be replaced during one).
+func (me Schema) compile() (colls compiledSchema, err error) {
+	names := map[string]string{}
+	colls = make(compiledSchema, len(recTypes))
+	for coll, rt := range recTypes {
+		cs := me[coll]
+		if cs == nil {
+			return nil, fmt.Errorf("schema: missing collection %#v", coll)
+		} else if len(cs.Name) == 0 {
+			return nil, fmt.Errorf("schema: %s: empty collection name", coll)
+		} else if other, ok := names[cs.Name]; ok {
+			return nil, fmt.Errorf("schema: %s and %s share the same collection name %#v", other, coll, cs.Name)
+		} else if _, ok = cs.Fields["_id"]; !ok {
+			return nil, fmt.Errorf("schema: %s: no `_id` field", coll)
+		}
+		names[cs.Name] = coll
+		fp := make(fieldPaths, len(cs.Fields))
+		cc := &compiledColl{name: cs.Name, paths: fp}
+		colls[coll] = cc
+		for field, path := range cs.Fields {
+			if len(field) == 0 || strings.Contains(field, ".") || strings.HasPrefix(field, "$") {
+				return nil, fmt.Errorf("schema: %s: invalid field name %#v", coll, field)
+			}
+			if fp[field], err = fieldIndex(rt, path); err != nil {
+				return nil, fmt.Errorf("schema: %s.%s: %s", coll, field, err.Error())
+			}
+		}
+		for _, index := range cs.Indexes {
+			if len(index) == 0 {
+				return nil, fmt.Errorf("schema: %s: empty index", coll)
+			}
+			k := make(bson.D, 0, len(index))
+			for _, key := range index {
+				field, order := indexKey(key)
+				if fp[field] == nil {
+					return nil, fmt.Errorf("schema: %s: index key %#v refers to unknown field", coll, key)
+				}
+				k = append(k, bson.E{Key: field, Value: order})
+			}
+			cc.indexes = append(cc.indexes, k)
+		}
+	}
+	return
+}
+
+func fieldIndex(rt reflect.Type, path string) (index []int, err error) {
+	for _, name := range strings.Split(path, ".") {
+		if rt.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("%#v: %s is not a struct", path, rt)
+		}
+		sf, ok := rt.FieldByName(name)
+		if !ok || len(sf.PkgPath) > 0 {
+			return nil, fmt.Errorf("%#v: no such field %#v in %s", path, name, rt)
+		}
+		index, rt = append(index, sf.Index...), sf.Type
+	}
+	return
+}
+
+func indexKey(key string) (field string, order interface{}) {
+	if field, order = key, 1; strings.HasSuffix(key, ":2dsphere") {
+		field, order = strings.TrimSuffix(key, ":2dsphere"), "2dsphere"
+	} else if strings.HasSuffix(key, ":-1") {
+		field, order = strings.TrimSuffix(key, ":-1"), -1
+	}
+	return
+}
+
+func doc(paths fieldPaths, rec interface{}) bson.M {
+	rv := reflect.Indirect(reflect.ValueOf(rec))
+	m := make(bson.M, len(paths))
+	for field, index := range paths {
+		m[field] = rv.FieldByIndex(index).Interface()
+	}
+	return sparse(m)
+}
@@ -0,0 +1,164 @@
+//	Keeps all normalized records of an import (via `import-dumps` package) in memory, with lookups by id, reference and code.
+package geonames_mem
+
+import (
+	"github.com/go-geo/geonames/import-dumps"
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+var (
+	//	Whether `Load` should `log.Printf` progress
+	Log = true
+)
+
+//	An in-memory `geonames_import.Sink`. All fields and methods are safe for concurrent reads once loading has finished.
+type Store struct {
+	//	All records, in import order. `Countries`, `Features` and `Timezones` are indexed by their `Ref - 1`.
+	Admins    []*geonames_import.Admin
+	Countries []*geonames_import.Country
+	Features  []*geonames_import.Feature
+	Places    []*geonames_import.Place
+	Postals   []*geonames_import.Postal
+	Timezones []*geonames_import.Timezone
+
+	//	Names of `geonames_import` collections not to be retained (for example `geonames_import.Postals` if not needed)
+	Skip map[string]bool
+
+	coll           string
+	adminsByCode   map[string]*geonames_import.Admin
+	adminsById     map[int64]*geonames_import.Admin
+	countriesByIso map[string]*geonames_import.Country
+	featuresByCode map[string]*geonames_import.Feature
+	placesById     map[int64]*geonames_import.Place
+	timezonesByZid map[string]*geonames_import.Timezone
+}
+
+//	Loads all records from `geo` into a new `Store`, except for the collections named in `skip`.
+func Load(geo *geonames_parse.Iterator, skip ...string) (me *Store, err error) {
+	me = NewStore()
+	for _, name := range skip {
+		me.Skip[name] = true
+	}
+	imp := geonames_import.NewImporter(me)
+	imp.Log = Log
+	if err = imp.Run(geo); err != nil {
+		me = nil
+	}
+	return
+}
+
+//	Initializes a new, empty `Store`.
+func NewStore() (me *Store) {
+	me = &Store{Skip: map[string]bool{}}
+	return
+}
+
+//	Returns the `Admin` with the specified geo-name `id`, or `nil`.
+func (me *Store) Admin(id int64) *geonames_import.Admin {
+	return me.adminsById[id]
+}
+
+//	Returns the `Admin` with the specified full `code` (eg. `US.CA` or `US.CA.037`), or `nil`.
+func (me *Store) AdminByCode(code string) *geonames_import.Admin {
+	return me.adminsByCode[code]
+}
+
+//	Returns the `Country` with the specified `ref`, or `nil`.
+func (me *Store) Country(ref int) *geonames_import.Country {
+	if ref > 0 && ref <= len(me.Countries) {
+		return me.Countries[ref-1]
+	}
+	return nil
+}
+
+//	Returns the `Country` with the specified ISO-3166 alpha-2 `code`, or `nil`.
+func (me *Store) CountryByCode(code string) *geonames_import.Country {
+	return me.countriesByIso[code]
+}
+
+//	Returns the `Feature` with the specified `ref`, or `nil`.
+func (me *Store) Feature(ref int) *geonames_import.Feature {
+	if ref > 0 && ref <= len(me.Features) {
+		return me.Features[ref-1]
+	}
+	return nil
+}
+
+//	Returns the `Feature` with the specified `code` (eg. `P.PPLC`), or `nil`.
+func (me *Store) FeatureByCode(code string) *geonames_import.Feature {
+	return me.featuresByCode[code]
+}
+
+//	Returns the `Place` with the specified geo-name `id`, or `nil`.
+func (me *Store) Place(id int64) *geonames_import.Place {
+	return me.placesById[id]
+}
+
+//	Returns the `Timezone` with the specified `ref`, or `nil`.
+func (me *Store) Timezone(ref int) *geonames_import.Timezone {
+	if ref > 0 && ref <= len(me.Timezones) {
+		return me.Timezones[ref-1]
+	}
+	return nil
+}
+
+//	Returns the `Timezone` with the specified IANA `TimezoneName` (eg. `Europe/Berlin`), or `nil`.
+func (me *Store) TimezoneByName(name string) *geonames_import.Timezone {
+	return me.timezonesByZid[name]
+}
+
+//	Implements `geonames_import.Sink` interface.
+func (me *Store) BeginCollection(name string, capHint int) (err error) {
+	if me.coll = name; !me.Skip[name] {
+		switch name {
+		case geonames_import.Admins:
+			me.Admins = make([]*geonames_import.Admin, 0, capHint)
+			me.adminsByCode, me.adminsById = make(map[string]*geonames_import.Admin, capHint), make(map[int64]*geonames_import.Admin, capHint)
+		case geonames_import.Countries:
+			me.Countries, me.countriesByIso = make([]*geonames_import.Country, 0, capHint), make(map[string]*geonames_import.Country, capHint)
+		case geonames_import.Features:
+			me.Features, me.featuresByCode = make([]*geonames_import.Feature, 0, capHint), make(map[string]*geonames_import.Feature, capHint)
+		case geonames_import.Places:
+			me.Places, me.placesById = make([]*geonames_import.Place, 0, capHint), make(map[int64]*geonames_import.Place, capHint)
+		case geonames_import.Postals:
+			me.Postals = make([]*geonames_import.Postal, 0, capHint)
+		case geonames_import.Timezones:
+			me.Timezones, me.timezonesByZid = make([]*geonames_import.Timezone, 0, capHint), make(map[string]*geonames_import.Timezone, capHint)
+		}
+	}
+	return
+}
+
+//	Implements `geonames_import.Sink` interface.
+func (me *Store) EndCollection(name string) (err error) {
+	me.coll = ""
+	return
+}
+
+//	Implements `geonames_import.Sink` interface.
+func (me *Store) Finish() (err error) {
+	return
+}
+
+//	Implements `geonames_import.Sink` interface.
+func (me *Store) Write(recs []interface{}) (err error) {
+	if !me.Skip[me.coll] {
+		for _, rec := range recs {
+			switch r := rec.(type) {
+			case *geonames_import.Admin:
+				me.Admins, me.adminsByCode[r.Code], me.adminsById[r.Id] = append(me.Admins, r), r, r
+			case *geonames_import.Country:
+				me.Countries, me.countriesByIso[r.Code.Iso2] = append(me.Countries, r), r
+			case *geonames_import.Feature:
+				me.Features, me.featuresByCode[r.Code] = append(me.Features, r), r
+			case *geonames_import.Place:
+				me.Places, me.placesById[r.Id] = append(me.Places, r), r
+			case *geonames_import.Postal:
+				me.Postals = append(me.Postals, r)
+			case *geonames_import.Timezone:
+				me.Timezones, me.timezonesByZid[r.TimezoneName] = append(me.Timezones, r), r
+			}
+		}
+	}
+	return
+}
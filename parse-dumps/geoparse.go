@@ -2,11 +2,12 @@
 package geonames_parse
 
 import (
+	"bufio"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/metaleap/go-util/fs"
 	"github.com/metaleap/go-util/geo"
 	"github.com/metaleap/go-util/slice"
 	"github.com/metaleap/go-util/str"
@@ -25,8 +26,16 @@ type Iterator struct {
 	DirPath string
 
 	FileNames struct {
-		Admin1, Admin2, Countries, Features, Hierarchy, Languages, Places, Postal, Timezones string
+		Admin1, Admin2, AlternateNames, Countries, Features, Hierarchy, Languages, Places, Postal, Timezones string
 	}
+
+	//	If set, called with the raw values of present but out-of-range coordinates (which are dropped, leaving `LonLat` `nil`),
+	//	right before the `onRec` call for the same record
+	OnInvalidLonLat func(fileName string, index int, lon, lat string)
+
+	//	If set, called before each record: a non-`nil` result stops the iteration right away and is returned by it
+	//	(so that `onRec` callbacks, which cannot return errors, can abort it)
+	Stop func() error
 }
 
 //	Initializes `me.DirPath` and all `me.FileNames`.
@@ -35,6 +44,7 @@ func NewIterator(dirPath string) (me *Iterator) {
 	fn := &me.FileNames
 	fn.Admin1 = "admin1CodesASCII.txt"
 	fn.Admin2 = "admin2Codes.txt"
+	fn.AlternateNames = "alternateNames.txt"
 	fn.Countries = "countryInfo.txt"
 	fn.Features = "featureCodes_en.txt"
 	fn.Hierarchy = "hierarchy.txt"
@@ -50,17 +60,34 @@ func (me *Iterator) iterate(fileName string, skipFirst bool, i int, onRec func(i
 	if file != nil {
 		defer file.Close()
 		if err == nil {
-			err = ufs.ReadLines(file, skipFirst, func(ln string) {
-				if !strings.HasPrefix(ln, "#") {
+			scanner := bufio.NewScanner(file)
+			scanner.Buffer(make([]byte, 0, 1<<16), 1<<24)
+			for first := true; err == nil && scanner.Scan(); first = false {
+				if ln := scanner.Text(); !(first && skipFirst) && !strings.HasPrefix(ln, "#") {
+					if me.Stop != nil {
+						if err = me.Stop(); err != nil {
+							break
+						}
+					}
 					onRec(i, uslice.StrEach(ustr.Split(ln, "\t"), strings.TrimSpace, ustr.ReduceSpaces))
 					i++
 				}
-			})
+			}
+			if err == nil {
+				err = scanner.Err()
+			}
 		}
 	}
 	return i, err
 }
 
+func (me *Iterator) lonLat(fileName string, index int, lon, lat string) (lonLat []float64) {
+	if lonLat = checkLonLat(ustr.ParseFloats(lon, lat)); lonLat == nil && me.OnInvalidLonLat != nil && (len(lon) > 0 || len(lat) > 0) {
+		me.OnInvalidLonLat(fileName, index, lon, lat)
+	}
+	return
+}
+
 func (me *Iterator) admin(fileName string, i int, onRec func(int, *AdminRec)) (int, error) {
 	var r AdminRec
 	return me.iterate(fileName, false, i, func(index int, rec []string) {
@@ -106,6 +133,32 @@ func (me *Iterator) AdminAll(onRec func(index int, rec *AdminRec)) (err error) {
 	return
 }
 
+//	Calls `onRec` for each `AlternateNameRec` found in `me.FileNames.AlternateNames`. Rows lacking any of the first 4 columns are skipped,
+//	missing flag columns are read as `false`.
+//
+//	The `rec` pointer always points to the exact same `struct`, but its field values differ for each `onRec` invocation.
+func (me *Iterator) AlternateNames(onRec func(index int, rec *AlternateNameRec)) (err error) {
+	var r AlternateNameRec
+	_, err = me.iterate(me.FileNames.AlternateNames, false, 0, func(index int, rec []string) {
+		if len(rec) < 4 {
+			return
+		}
+		for len(rec) < 8 {
+			rec = append(rec, "")
+		}
+		r.Id = ustr.ParseInt(rec[0])
+		r.PlaceId = ustr.ParseInt(rec[1])
+		r.Language = rec[2]
+		r.Name = rec[3]
+		r.Preferred = rec[4] == "1"
+		r.Short = rec[5] == "1"
+		r.Colloquial = rec[6] == "1"
+		r.Historic = rec[7] == "1"
+		onRec(index, &r)
+	})
+	return
+}
+
 //	Calls `onRec` for each `CountryRec` found in `me.FileNames.Countries`.
 //
 //	The `rec` pointer always points to the exact same `struct`, but its field values differ for each `onRec` invocation.
@@ -130,6 +183,10 @@ func (me *Iterator) Countries(onRec func(index int, rec *CountryRec)) (err error
 		r.Languages = uslice.StrEach(ustr.Split(rec[15], ","), strings.TrimSpace)
 		r.Id = ustr.ParseInt(rec[16])
 		r.Neighbors = uslice.StrEach(ustr.Split(rec[17], ","), strings.TrimSpace)
+		r.EquivalentFipsCode = ""
+		if len(rec) > 18 {
+			r.EquivalentFipsCode = rec[18]
+		}
 		onRec(index, &r)
 	})
 	return
@@ -188,7 +245,7 @@ func (me *Iterator) Places(onRec func(index int, rec *PlaceRec)) (err error) {
 		r.Name = rec[1]
 		r.NameAscii = rec[2]
 		r.NamesAlt = uslice.StrEach(ustr.Split(rec[3], ","), strings.TrimSpace)
-		r.LonLat = checkLonLat(ustr.ParseFloats(rec[5], rec[4]))
+		r.LonLat = me.lonLat(me.FileNames.Places, index, rec[5], rec[4])
 		r.Feature.Class = rec[6]
 		r.Feature.Code = rec[7]
 		r.Country.Code = rec[8]
@@ -199,10 +256,14 @@ func (me *Iterator) Places(onRec func(index int, rec *PlaceRec)) (err error) {
 		r.Admin.Code4 = rec[13]
 		r.Population = ustr.ParseInt(rec[14])
 		r.Elevation = ustr.ParseInt(rec[15])
-		if el2 := ustr.ParseInt(rec[16]); el2 != 0 && r.Elevation == 0 {
-			r.Elevation = el2
+		if r.Dem = ustr.ParseInt(rec[16]); r.Elevation == 0 {
+			r.Elevation = r.Dem
 		}
 		r.TimezoneName = rec[17]
+		r.Modified = time.Time{}
+		if len(rec) > 18 && len(rec[18]) > 0 {
+			r.Modified, _ = time.Parse("2006-01-02", rec[18])
+		}
 
 		if r.Name == r.NameAscii {
 			r.NameAscii = ""
@@ -232,7 +293,7 @@ func (me *Iterator) PostalCodes(onRec func(index int, rec *PostalRec)) (err erro
 		r.Admin.Code2 = rec[6]
 		r.Admin.Name3 = rec[7]
 		r.Admin.Code3 = rec[8]
-		r.LonLat = checkLonLat(ustr.ParseFloats(rec[10], rec[9]))
+		r.LonLat = me.lonLat(me.FileNames.Postal, index, rec[10], rec[9])
 		r.Accuracy = ustr.ParseInt(rec[11])
 		onRec(index, &r)
 	})
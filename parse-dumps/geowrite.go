@@ -0,0 +1,171 @@
+package geonames_parse
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	//	Header lines written by `Writer.Header`, keyed by the default `Iterator.FileNames`.
+	//	`Iterator` skips the first line of the languages and time-zones files, and all `#` comment lines.
+	Headers = map[string]string{
+		"countryInfo.txt":       "#ISO\tISO3\tISO-Numeric\tfips\tCountry\tCapital\tArea(in sq km)\tPopulation\tContinent\ttld\tCurrencyCode\tCurrencyName\tPhone\tPostal Code Format\tPostal Code Regex\tLanguages\tgeonameid\tneighbours\tEquivalentFipsCode",
+		"iso-languagecodes.txt": "ISO 639-3\tISO 639-2\tISO 639-1\tLanguage Name",
+		"timeZones.txt":         "CountryCode\tTimeZoneId\tGMT offset 1. Jan 2024\tDST offset 1. Jul 2024\trawOffset (independant of DST)",
+	}
+)
+
+//	Writes records in the tab-separated format of the raw `download.geonames.org/export/dump` files, one line per record,
+//	for other GeoNames tooling (and `Iterator`) to consume.
+//
+//	Reading back the output with `Iterator` yields the exact same records, and the lines written are byte-identical to the original ones
+//	except where parsing normalizes: `AreaSqKm` is an integer, `PlaceRec.NamesAlt` no longer contain `Name` and `NameAscii`,
+//	an `elevation` equal to the `dem` is left empty, zero ids, `Elevation` and `Accuracy` are written as empty columns,
+//	coordinates are written in their shortest form (and dropped if invalid), and whitespace is trimmed and reduced.
+//	Tabs and line breaks in values are written as spaces. Comment lines of the original files other than `Headers` are not reproduced.
+type Writer struct {
+	//	Number of records written so far, excluding headers
+	Count int
+
+	w *bufio.Writer
+}
+
+//	Initializes a new `Writer` writing to `w`. `Flush` must be called to complete the output.
+func NewWriter(w io.Writer) (me *Writer) {
+	me = &Writer{w: bufio.NewWriterSize(w, 1<<16)}
+	return
+}
+
+//	Flushes all buffered output. Does not close the underlying `io.Writer`.
+func (me *Writer) Flush() error {
+	return me.w.Flush()
+}
+
+//	Writes the `Headers` line for `fileName` (one of the default `Iterator.FileNames`), if any.
+//	Must be called before any records for files whose first line `Iterator` skips.
+func (me *Writer) Header(fileName string) (err error) {
+	if header := Headers[fileName]; len(header) > 0 {
+		if _, err = me.w.WriteString(header); err == nil {
+			err = me.w.WriteByte('\n')
+		}
+	}
+	return
+}
+
+//	Writes `r` as read by `Iterator.Admin1` or `Iterator.Admin2`.
+func (me *Writer) Admin(r *AdminRec) error {
+	return me.line(r.Code, r.Name, or(r.NameAscii, r.Name), itoa(r.Id))
+}
+
+//	Writes `r` as read by `Iterator.AlternateNames`.
+func (me *Writer) AlternateName(r *AlternateNameRec) error {
+	return me.line(itoa(r.Id), itoa(r.PlaceId), r.Language, r.Name, flag(r.Preferred), flag(r.Short), flag(r.Colloquial), flag(r.Historic))
+}
+
+//	Writes `r` as read by `Iterator.Countries`.
+func (me *Writer) Country(r *CountryRec) error {
+	return me.line(r.Code.Iso2, r.Code.Iso3, r.Code.IsoNum, r.Code.Fips, r.Name, r.Capital, strconv.FormatInt(r.AreaSqKm, 10),
+		strconv.FormatInt(r.Population, 10), r.Continent, r.Tld, r.Currency.Code, r.Currency.Name, r.CallingCode, r.PostalCode.Format,
+		r.PostalCode.Regex, strings.Join(r.Languages, ","), itoa(r.Id), strings.Join(r.Neighbors, ","), r.EquivalentFipsCode)
+}
+
+//	Writes `r` as read by `Iterator.Features`.
+func (me *Writer) Feature(r *FeatureRec) error {
+	return me.line(r.Code, r.Name, r.Desc)
+}
+
+//	Writes `r` as read by `Iterator.Hierarchy`.
+func (me *Writer) Hierarchy(r *HierarchyRec) error {
+	return me.line(itoa(r.ParentId), itoa(r.ChildId), r.Type)
+}
+
+//	Writes `r` as read by `Iterator.Languages`.
+func (me *Writer) Language(r *LanguageRec) error {
+	return me.line(r.Iso_639_3, r.Iso_639_2, r.Iso_639_1, r.Name)
+}
+
+//	Writes `r` as read by `Iterator.Places`.
+func (me *Writer) Place(r *PlaceRec) error {
+	lon, lat := lonLat(r.LonLat)
+	var modified, elevation string
+	if !r.Modified.IsZero() {
+		modified = r.Modified.Format("2006-01-02")
+	}
+	if r.Elevation != r.Dem {
+		elevation = itoa(r.Elevation)
+	}
+	return me.line(itoa(r.Id), r.Name, or(r.NameAscii, r.Name), strings.Join(r.NamesAlt, ","), lat, lon, r.Feature.Class, r.Feature.Code,
+		r.Country.Code, strings.Join(r.Country.CodesAlt, ","), r.Admin.Code1, r.Admin.Code2, r.Admin.Code3, r.Admin.Code4,
+		strconv.FormatInt(r.Population, 10), elevation, strconv.FormatInt(r.Dem, 10), r.TimezoneName, modified)
+}
+
+//	Writes `r` as read by `Iterator.PostalCodes`.
+func (me *Writer) Postal(r *PostalRec) error {
+	lon, lat := lonLat(r.LonLat)
+	return me.line(r.CountryCode, r.PostalCode, r.PlaceName, r.Admin.Name1, r.Admin.Code1, r.Admin.Name2, r.Admin.Code2,
+		r.Admin.Name3, r.Admin.Code3, lat, lon, itoa(r.Accuracy))
+}
+
+//	Writes `r` as read by `Iterator.Timezones`.
+func (me *Writer) Timezone(r *TimezoneRec) error {
+	return me.line(r.CountryCode, r.TimezoneName, offset(r.OffsetGmt), offset(r.OffsetDst), offset(r.OffsetRaw))
+}
+
+func (me *Writer) line(fields ...string) (err error) {
+	for i, field := range fields {
+		if i > 0 {
+			if err = me.w.WriteByte('\t'); err != nil {
+				return
+			}
+		}
+		if strings.ContainsAny(field, "\t\r\n") {
+			field = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(field)
+		}
+		if _, err = me.w.WriteString(field); err != nil {
+			return
+		}
+	}
+	if err = me.w.WriteByte('\n'); err == nil {
+		me.Count++
+	}
+	return
+}
+
+func flag(b bool) string {
+	if b {
+		return "1"
+	}
+	return ""
+}
+
+//	Formats non-zero ids and counts, and zero ones as empty columns.
+func itoa(i int64) string {
+	if i == 0 {
+		return ""
+	}
+	return strconv.FormatInt(i, 10)
+}
+
+func lonLat(ll []float64) (lon, lat string) {
+	if len(ll) == 2 {
+		lon, lat = strconv.FormatFloat(ll[0], 'f', -1, 64), strconv.FormatFloat(ll[1], 'f', -1, 64)
+	}
+	return
+}
+
+//	Formats a time-zone offset with at least one decimal, as in `1.0` or `5.75`.
+func offset(hours float64) (s string) {
+	if s = strconv.FormatFloat(hours, 'f', -1, 64); !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return
+}
+
+func or(s, fallback string) string {
+	if len(s) == 0 {
+		return fallback
+	}
+	return s
+}
@@ -0,0 +1,153 @@
+package geonames_parse
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readAll[R any](t *testing.T, geo *Iterator, iterate func(*Iterator, func(int, *R)) error) (recs []R) {
+	if err := iterate(geo, func(_ int, r *R) { recs = append(recs, *r) }); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+//	Parses `testdata/<fileName>` via `iterate`, writes all records with `write`, and checks that the output
+//	is byte-identical to the (already normalized) fixture and parses back into the same records.
+func roundTrip[R any](t *testing.T, fileName string, iterate func(*Iterator, func(int, *R)) error, write func(*Writer, *R) error) {
+	original, err := os.ReadFile(filepath.Join("testdata", fileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recs := readAll(t, NewIterator("testdata"), iterate)
+	if len(recs) == 0 {
+		t.Fatalf("%s: no records", fileName)
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err = w.Header(fileName); err == nil {
+		for i := range recs {
+			if err = write(w, &recs[i]); err != nil {
+				break
+			}
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		t.Fatal(err)
+	} else if w.Count != len(recs) {
+		t.Errorf("%s: Count is %d, expected %d", fileName, w.Count, len(recs))
+	}
+	if !bytes.Equal(buf.Bytes(), original) {
+		t.Errorf("%s: written\n%s\nexpected\n%s", fileName, buf.Bytes(), original)
+	}
+
+	dirPath := t.TempDir()
+	if err = os.WriteFile(filepath.Join(dirPath, fileName), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if again := readAll(t, NewIterator(dirPath), iterate); !reflect.DeepEqual(again, recs) {
+		t.Errorf("%s: read back\n%+v\nexpected\n%+v", fileName, again, recs)
+	}
+}
+
+func TestWriteCountries(t *testing.T) {
+	roundTrip(t, "countryInfo.txt", (*Iterator).Countries, (*Writer).Country)
+}
+
+func TestWritePlaces(t *testing.T) {
+	roundTrip(t, "allCountries.txt", (*Iterator).Places, (*Writer).Place)
+}
+
+func TestWritePostals(t *testing.T) {
+	roundTrip(t, "zip_allCountries.txt", (*Iterator).PostalCodes, (*Writer).Postal)
+}
+
+func TestWriteAdmins(t *testing.T) {
+	roundTrip(t, "admin1CodesASCII.txt", (*Iterator).Admin1, (*Writer).Admin)
+	roundTrip(t, "admin2Codes.txt", (*Iterator).Admin2, (*Writer).Admin)
+}
+
+func TestWriteTimezones(t *testing.T) {
+	roundTrip(t, "timeZones.txt", (*Iterator).Timezones, (*Writer).Timezone)
+}
+
+//	Parses `testdata/dump/<fileName>` (in the exact form of the raw dumps) via `iterate`, writes all records with `write`, and checks
+//	that each written line equals its original one, except for the lines in `changed` (keyed by their index among the records).
+func normalizes[R any](t *testing.T, fileName string, iterate func(*Iterator, func(int, *R)) error, write func(*Writer, *R) error, changed map[int]string) {
+	original, err := os.ReadFile(filepath.Join("testdata", "dump", fileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, ln := range strings.Split(strings.TrimSuffix(string(original), "\n"), "\n") {
+		if !strings.HasPrefix(ln, "#") {
+			lines = append(lines, ln)
+		}
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err = w.Header(fileName); err == nil {
+		err = iterate(NewIterator(filepath.Join("testdata", "dump")), func(_ int, r *R) {
+			if err == nil {
+				err = write(w, r)
+			}
+		})
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	written := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if header := Headers[fileName]; len(header) > 0 {
+		if written[0] != header {
+			t.Errorf("%s: header %q, expected %q", fileName, written[0], header)
+		}
+		written = written[1:]
+	}
+	if len(written) != len(lines) {
+		t.Fatalf("%s: %d lines written, expected %d", fileName, len(written), len(lines))
+	}
+	for i, ln := range lines {
+		if expected, ok := changed[i]; ok {
+			if written[i] == ln {
+				t.Errorf("%s:%d: written unchanged, expected\n%s", fileName, i, expected)
+			}
+			ln = expected
+		}
+		if written[i] != ln {
+			t.Errorf("%s:%d: written\n%s\nexpected\n%s", fileName, i, written[i], ln)
+		}
+	}
+}
+
+func TestWriteNormalizes(t *testing.T) {
+	normalizes(t, "allCountries.txt", (*Iterator).Places, (*Writer).Place, map[int]string{
+		//	`NamesAlt` without `Name`, empty `elevation` stays empty (as it equals the `dem`)
+		0: "2867714\tMunich\tMunich\tLungsod ng Munich,Monaco di Baviera,Munchen,Muenchen,München\t48.13743\t11.57549\tP\tPPLA\tDE\t\t02\t091\t09162\t09162000\t1260391\t\t524\tEurope/Berlin\t2023-10-26",
+		//	`NamesAlt` without `Name`, `elevation` equal to the `dem` left empty
+		1: "2950159\tBerlin\tBerlin\tBER,Berlino,Berolinum\t52.52437\t13.41053\tP\tPPLC\tDE\t\t16\t00\t11000\t11000000\t3426354\t\t74\tEurope/Berlin\t2022-06-01",
+		//	`NamesAlt` only `Name`, coordinates in their shortest form
+		2: "2960313\tZugspitze\tZugspitze\t\t47.4211\t10.9853\tT\tPK\tDE\tAT\t02\t091\t09180\t\t0\t2962\t2940\tEurope/Berlin\t2021-03-14",
+		//	whitespace trimmed and reduced, coordinates in their shortest form
+		3: "3020251\tNorth Sea\tNorth Sea\tMar del Norte,Nordsee\t55\t3\tH\tSEA\t\t\t\t00\t\t\t0\t\t-9999\t\t2012-01-18",
+	})
+	//	zero `population` and `0`-padded `ISO-Numeric` unchanged, comment lines other than `Headers` dropped
+	normalizes(t, "countryInfo.txt", (*Iterator).Countries, (*Writer).Country, nil)
+	normalizes(t, "zip_allCountries.txt", (*Iterator).PostalCodes, (*Writer).Postal, map[int]string{
+		//	coordinates in their shortest form
+		0: "DE\t80331\tMünchen\tBayern\tBY\tUpper Bavaria\t091\tKreisfreie Stadt München\t09162\t48.1345\t11.571\t4",
+		//	coordinates in their shortest form, `0`-padded admin codes unchanged
+		2: "DE\t01067\tDresden\tSachsen\tSN\t\t00\tKreisfreie Stadt Dresden\t14612\t51.06\t13.72\t",
+	})
+}
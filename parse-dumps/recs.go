@@ -1,5 +1,9 @@
 package geonames_parse
 
+import (
+	"time"
+)
+
 //	admin1CodesASCII.txt and admin2Codes.txt
 type AdminRec struct {
 	Code      string
@@ -8,6 +12,18 @@ type AdminRec struct {
 	Id        int64
 }
 
+//	alternateNames.txt
+type AlternateNameRec struct {
+	Id         int64
+	PlaceId    int64
+	Language   string
+	Name       string
+	Preferred  bool
+	Short      bool
+	Colloquial bool
+	Historic   bool
+}
+
 //	countryInfo.txt
 type CountryRec struct {
 	Code struct {
@@ -31,9 +47,10 @@ type CountryRec struct {
 		Format string
 		Regex  string
 	}
-	Languages []string
-	Id        int64
-	Neighbors []string
+	Languages          []string
+	Id                 int64
+	Neighbors          []string
+	EquivalentFipsCode string
 }
 
 //	featureCodes_en.txt
@@ -76,9 +93,13 @@ type PlaceRec struct {
 	Admin struct {
 		Code1, Code2, Code3, Code4 string
 	}
-	Population   int64
-	Elevation    int64
+	Population int64
+	//	The `elevation` column, or `Dem` if that is empty or `0`
+	Elevation int64
+	//	The `dem` column (digital elevation model)
+	Dem          int64
 	TimezoneName string
+	Modified     time.Time
 }
 
 //	zip_allCountries.txt
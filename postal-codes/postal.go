@@ -0,0 +1,155 @@
+//	In-process postal-code lookups over the `Postals` of a `mem-store`: by country and code, by code prefix within a country, and nearest to any coordinate.
+package geonames_postal
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-geo/geonames/import-dumps"
+	"github.com/go-geo/geonames/mem-store"
+	"github.com/go-geo/geonames/spatial-index"
+)
+
+//	Restricts the results of `Index.Nearest`. All non-zero conditions must be met.
+type Filter struct {
+	//	Acceptable ISO-3166 alpha-2 country codes
+	Countries []string
+
+	//	Maximum distance in kilometers
+	MaxDistanceKm float64
+}
+
+func (me *Filter) accepts(p *geonames_import.Postal) bool {
+	if len(me.Countries) > 0 {
+		for _, c := range me.Countries {
+			if c == p.CountryCode {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+//	A postal-code lookup result.
+type Result struct {
+	//	The postal-code record found, with its place name, admin names and coordinates
+	Postal *geonames_import.Postal
+
+	//	Great-circle distance in kilometers from the queried coordinates (for `Index.Nearest`), else `0`
+	DistanceKm float64
+
+	//	The postal code's country, or `nil` if unknown
+	Country *geonames_import.Country
+}
+
+//	A read-only index over (a subset of) the `Postals` of a `geonames_mem.Store`, safe for concurrent use.
+type Index struct {
+	//	The records looked up by `Index` methods
+	Store *geonames_mem.Store
+
+	//	The `Store.Postals` indexed, sorted by `CountryCode`, then by upper-cased `PostalCode`
+	Postals []*geonames_import.Postal
+
+	//	Spatial index over `Postals`
+	Tree *geonames_spatial.Tree
+
+	codes     []string
+	countries map[string][2]int
+}
+
+//	Returns `code` trimmed and upper-cased, as compared by `Index` lookups.
+func Key(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+//	Builds an `Index` over all `store.Postals` for which `include` returns `true` (or all of them if `include` is `nil`).
+func NewIndex(store *geonames_mem.Store, include func(*geonames_import.Postal) bool) (me *Index) {
+	me = &Index{Store: store, Postals: make([]*geonames_import.Postal, 0, len(store.Postals)), countries: map[string][2]int{}}
+	for _, p := range store.Postals {
+		if include == nil || include(p) {
+			me.Postals = append(me.Postals, p)
+		}
+	}
+	sort.SliceStable(me.Postals, func(i, j int) bool {
+		if pi, pj := me.Postals[i], me.Postals[j]; pi.CountryCode != pj.CountryCode {
+			return pi.CountryCode < pj.CountryCode
+		} else {
+			return Key(pi.PostalCode) < Key(pj.PostalCode)
+		}
+	})
+	me.codes = make([]string, len(me.Postals))
+	for i, p := range me.Postals {
+		me.codes[i] = Key(p.PostalCode)
+		r, ok := me.countries[p.CountryCode]
+		if !ok {
+			r[0] = i
+		}
+		r[1] = i + 1
+		me.countries[p.CountryCode] = r
+	}
+	me.Tree = geonames_spatial.NewTree(len(me.Postals), func(i int) (float64, float64) {
+		return me.Postals[i].LonLat[0], me.Postals[i].LonLat[1]
+	})
+	return
+}
+
+//	Returns the ISO-3166 alpha-2 codes of all countries having indexed postal codes, sorted.
+func (me *Index) Countries() (codes []string) {
+	codes = make([]string, 0, len(me.countries))
+	for c := range me.countries {
+		codes = append(codes, c)
+	}
+	sort.Strings(codes)
+	return
+}
+
+//	Returns all records of the specified postal `code` (case-insensitive) in the specified `country` (ISO-3166 alpha-2).
+//	There may be several, as one postal code frequently covers more than one place.
+func (me *Index) Lookup(country, code string) (results []Result) {
+	key := Key(code)
+	me.scan(country, key, func(c string) bool { return c == key }, 0, &results)
+	return
+}
+
+//	Returns up to `max` records (all if `max` is `0`) in the specified `country` whose postal codes start with `prefix` (case-insensitive), in postal-code order.
+func (me *Index) Prefix(country, prefix string, max int) (results []Result) {
+	key := Key(prefix)
+	me.scan(country, key, func(c string) bool { return strings.HasPrefix(c, key) }, max, &results)
+	return
+}
+
+func (me *Index) scan(country, key string, match func(string) bool, max int, results *[]Result) {
+	if r, ok := me.countries[strings.ToUpper(country)]; ok {
+		for i := r[0] + sort.SearchStrings(me.codes[r[0]:r[1]], key); i < r[1] && match(me.codes[i]); i++ {
+			if *results = append(*results, me.result(me.Postals[i], 0)); max > 0 && len(*results) >= max {
+				break
+			}
+		}
+	}
+}
+
+//	Returns up to `k` indexed postal codes nearest to `lon`/`lat` (in degrees), nearest first. `filter` may be `nil`.
+func (me *Index) Nearest(lon, lat float64, k int, filter *Filter) (results []Result) {
+	var (
+		maxKm  float64
+		accept func(int) bool
+	)
+	if filter != nil {
+		maxKm, accept = filter.MaxDistanceKm, func(i int) bool { return filter.accepts(me.Postals[i]) }
+	}
+	nearest := me.Tree.Nearest(lon, lat, k, maxKm, accept)
+	results = make([]Result, len(nearest))
+	for i, n := range nearest {
+		results[i] = me.result(me.Postals[n.Item], n.DistanceKm)
+	}
+	return
+}
+
+func (me *Index) result(p *geonames_import.Postal, distKm float64) (r Result) {
+	r.Postal, r.DistanceKm = p, distKm
+	if r.Country = me.Store.Country(p.CountryRef); r.Country == nil {
+		r.Country = me.Store.CountryByCode(p.CountryCode)
+	}
+	return
+}
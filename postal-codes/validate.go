@@ -0,0 +1,131 @@
+package geonames_postal
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-geo/geonames/mem-store"
+)
+
+//	The outcome of `Validator.Validate`.
+type Validation struct {
+	//	The input normalized according to its country's postal-code format mask (such as `SW1A 1AA` for `sw1a1aa`),
+	//	or merely trimmed and upper-cased if no mask fits
+	Normalized string
+
+	//	Whether the country defines a postal-code format at all
+	Known bool
+
+	//	Whether `Normalized` fits the country's postal-code format (and regular expression, if any)
+	Valid bool
+
+	//	Whether `Validator.Index` has records for `Normalized` (always `false` if `Validator.Index` is `nil`)
+	Exists bool
+
+	//	The records found in `Validator.Index`, if any
+	Results []Result
+}
+
+//	Validates and normalizes postal codes according to the `PostalCode.Format` masks and `PostalCode.Regex`es of countries.
+//	Safe for concurrent use.
+type Validator struct {
+	//	If not `nil`, valid postal codes are also looked up here to set `Validation.Exists`
+	Index *Index
+
+	formats map[string]*format
+}
+
+type format struct {
+	masks []string
+	regex *regexp.Regexp
+}
+
+//	Initializes a new `Validator` over the postal-code formats of all `store.Countries`. `index` may be `nil`.
+//
+//	A country's regular expression is ignored if it fails to compile, in which case only its format masks apply.
+func NewValidator(store *geonames_mem.Store, index *Index) (me *Validator) {
+	me = &Validator{Index: index, formats: map[string]*format{}}
+	for _, c := range store.Countries {
+		if len(c.PostalCode.Format) > 0 || len(c.PostalCode.Regex) > 0 {
+			f := &format{}
+			for _, mask := range strings.Split(c.PostalCode.Format, "|") {
+				if mask = strings.TrimSpace(mask); len(mask) > 0 {
+					f.masks = append(f.masks, mask)
+				}
+			}
+			if re := strings.TrimSuffix(strings.TrimPrefix(c.PostalCode.Regex, "^"), "$"); len(re) > 0 {
+				// the dumps' expressions are of the form `^(a)|(b)$`, so anchor all alternatives
+				f.regex, _ = regexp.Compile("^(?:" + re + ")$")
+			}
+			me.formats[c.Code.Iso2] = f
+		}
+	}
+	return
+}
+
+//	Normalizes `code` for the specified `country` (ISO-3166 alpha-2) and validates the result.
+func (me *Validator) Validate(country, code string) (v Validation) {
+	country = strings.ToUpper(country)
+	v.Normalized = strings.Join(strings.Fields(strings.ToUpper(code)), " ")
+	if f := me.formats[country]; f != nil {
+		v.Known = true
+		compact := strings.NewReplacer(" ", "", "-", "").Replace(v.Normalized)
+		for _, mask := range f.masks {
+			if norm, ok := applyMask(mask, compact); ok && (f.regex == nil || f.regex.MatchString(norm)) {
+				v.Normalized, v.Valid = norm, true
+				break
+			}
+		}
+		if !v.Valid && f.regex != nil {
+			for _, norm := range []string{v.Normalized, compact} {
+				if f.regex.MatchString(norm) {
+					v.Normalized, v.Valid = norm, true
+					break
+				}
+			}
+		}
+	}
+	if v.Valid && me.Index != nil {
+		v.Results = me.Index.Lookup(country, v.Normalized)
+		v.Exists = len(v.Results) > 0
+	}
+	return
+}
+
+//	Same as `Validate(country, code).Valid`.
+func (me *Validator) Valid(country, code string) bool {
+	return me.Validate(country, code).Valid
+}
+
+//	Fits `compact` (upper-cased, without spaces or dashes) into `mask`, where `#` denotes a digit, `@` a letter and
+//	spaces or dashes are inserted as-is. Literal characters leading `mask` (such as `AD` in `AD###`) may be omitted from `compact`.
+func applyMask(mask, compact string) (string, bool) {
+	if prefix := mask[:strings.IndexAny(mask+"#", "#@ -")]; !strings.HasPrefix(compact, prefix) {
+		compact = prefix + compact
+	}
+	buf, j := make([]byte, 0, len(mask)), 0
+	for i := 0; i < len(mask); i++ {
+		switch m := mask[i]; {
+		case m == ' ' || m == '-':
+			buf = append(buf, m)
+			continue
+		case j >= len(compact):
+			return "", false
+		case m == '#':
+			if c := compact[j]; c < '0' || c > '9' {
+				return "", false
+			}
+		case m == '@':
+			if c := compact[j]; c < 'A' || c > 'Z' {
+				return "", false
+			}
+		default:
+			if compact[j] != strings.ToUpper(mask[i : i+1])[0] {
+				return "", false
+			}
+		}
+		buf = append(buf, compact[j])
+		j++
+	}
+	return string(buf), j == len(compact)
+}
@@ -0,0 +1,136 @@
+//	In-process reverse geocoding: finds the places nearest to any coordinate, using a `spatial-index` tree over the places of a `mem-store`.
+package geonames_reverse
+
+import (
+	"github.com/go-geo/geonames/import-dumps"
+	"github.com/go-geo/geonames/mem-store"
+	"github.com/go-geo/geonames/spatial-index"
+)
+
+//	Restricts the results of `Index.Nearest`. All non-zero conditions must be met.
+type Filter struct {
+	//	Acceptable feature classes, eg. `"P"` for populated places or `"PA"` for those and administrative divisions
+	FeatureClasses string
+
+	//	Acceptable feature codes (eg. `PPLC`, `PPLA`)
+	FeatureCodes []string
+
+	//	Acceptable ISO-3166 alpha-2 country codes
+	Countries []string
+
+	//	Minimum population
+	MinPopulation int64
+
+	//	Maximum distance in kilometers
+	MaxDistanceKm float64
+}
+
+func (me *Filter) accepts(p *geonames_import.Place) bool {
+	if p.Population < me.MinPopulation {
+		return false
+	}
+	if len(me.FeatureClasses) > 0 && !has(me.FeatureClasses, p.Feature.Class) {
+		return false
+	}
+	if len(me.FeatureCodes) > 0 && !in(me.FeatureCodes, p.Feature.Code) {
+		return false
+	}
+	if len(me.Countries) > 0 && !in(me.Countries, p.Country.Code) {
+		return false
+	}
+	return true
+}
+
+func has(chars, s string) bool {
+	for i := 0; i < len(chars); i++ {
+		if len(s) == 1 && s[0] == chars[i] {
+			return true
+		}
+	}
+	return false
+}
+
+func in(vals []string, s string) bool {
+	for _, v := range vals {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+//	A reverse-geocoding result.
+type Result struct {
+	//	The place found
+	Place *geonames_import.Place
+
+	//	Great-circle distance in kilometers from the queried coordinates
+	DistanceKm float64
+
+	//	The place's country, or `nil` if unknown
+	Country *geonames_import.Country
+
+	//	The place's first- and second-order administrative divisions, each `nil` if unknown
+	Admin1, Admin2 *geonames_import.Admin
+
+	//	IANA time zone name, eg. `Europe/Berlin`
+	Timezone string
+}
+
+//	A read-only spatial index over (a subset of) the `Places` of a `geonames_mem.Store`, safe for concurrent use.
+type Index struct {
+	//	The records looked up by `Nearest`
+	Store *geonames_mem.Store
+
+	//	The `Store.Places` indexed by `Tree`
+	Places []*geonames_import.Place
+
+	//	Spatial index over `Places`
+	Tree *geonames_spatial.Tree
+}
+
+//	Builds an `Index` over all `store.Places` for which `include` returns `true` (or all of them if `include` is `nil`).
+func NewIndex(store *geonames_mem.Store, include func(*geonames_import.Place) bool) (me *Index) {
+	me = &Index{Store: store, Places: make([]*geonames_import.Place, 0, len(store.Places))}
+	for _, p := range store.Places {
+		if include == nil || include(p) {
+			me.Places = append(me.Places, p)
+		}
+	}
+	me.Tree = geonames_spatial.NewTree(len(me.Places), func(i int) (float64, float64) {
+		return me.Places[i].LonLat[0], me.Places[i].LonLat[1]
+	})
+	return
+}
+
+//	Returns up to `k` indexed places nearest to `lon`/`lat` (in degrees), nearest first. `filter` may be `nil`.
+func (me *Index) Nearest(lon, lat float64, k int, filter *Filter) (results []Result) {
+	var (
+		maxKm  float64
+		accept func(int) bool
+	)
+	if filter != nil {
+		maxKm, accept = filter.MaxDistanceKm, func(i int) bool { return filter.accepts(me.Places[i]) }
+	}
+	nearest := me.Tree.Nearest(lon, lat, k, maxKm, accept)
+	results = make([]Result, len(nearest))
+	for i, n := range nearest {
+		results[i] = me.result(me.Places[n.Item], n.DistanceKm)
+	}
+	return
+}
+
+func (me *Index) result(p *geonames_import.Place, distKm float64) (r Result) {
+	r.Place, r.DistanceKm, r.Timezone = p, distKm, p.TimezoneName
+	if r.Country = me.Store.Country(p.CountryRef); r.Country == nil {
+		r.Country = me.Store.CountryByCode(p.Country.Code)
+	}
+	if len(p.Admin.Code1) > 0 {
+		code1 := p.Country.Code + "." + p.Admin.Code1
+		r.Admin1 = me.Store.AdminByCode(code1)
+		if len(p.Admin.Code2) > 0 {
+			r.Admin2 = me.Store.AdminByCode(code1 + "." + p.Admin.Code2)
+		}
+	}
+	return
+}
@@ -0,0 +1,75 @@
+package geonames_search
+
+import (
+	"strings"
+	"unicode"
+)
+
+var (
+	//	Maps accented and ligature letters to their unaccented (lower-case) ASCII equivalent
+	foldings = map[rune]string{}
+
+	//	Letters whose conventional transliteration differs from their mere `Fold`ing (`ü` is `ue`, not just `u`)
+	transliterations = strings.NewReplacer(
+		"ä", "ae", "ö", "oe", "ü", "ue", "Ä", "Ae", "Ö", "Oe", "Ü", "Ue",
+		"å", "aa", "Å", "Aa", "ø", "oe", "Ø", "Oe",
+	)
+)
+
+func init() {
+	for ascii, accented := range map[string]string{
+		"a": "àáâãäåāăąǎǻạảấầẩẫậắằẳẵặ", "ae": "æǽ", "c": "çćĉċč", "d": "ďđð", "e": "èéêëēĕėęěẹẻẽếềểễệə",
+		"g": "ĝğġģ", "h": "ĥħ", "i": "ìíîïĩīĭįıǐỉị", "ij": "ĳ", "j": "ĵ", "k": "ķ", "l": "ĺļľŀł",
+		"n": "ñńņňŉ", "o": "òóôõöøōŏőǒǿọỏốồổỗộớờởỡợơ", "oe": "œ", "r": "ŕŗř", "s": "śŝşšș", "ss": "ß",
+		"t": "ţťŧț", "th": "þ", "u": "ùúûüũūŭůűųǔǖǘǚǜụủứừửữựư", "w": "ŵ", "y": "ýÿŷỳỵỷỹ", "z": "źżž",
+	} {
+		for _, r := range accented {
+			foldings[r] = ascii
+		}
+	}
+}
+
+//	Returns the normalized form of `name` used for all index keys and lookups:
+//	lower-cased, with diacritics removed (`Zürich` becomes `zurich`), punctuation turned into spaces and all spaces reduced.
+func Fold(name string) string {
+	var buf strings.Builder
+	buf.Grow(len(name))
+	space := true
+	for _, r := range name {
+		r = unicode.ToLower(r)
+		if s, ok := foldings[r]; ok {
+			buf.WriteString(s)
+			space = false
+		} else if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			buf.WriteRune(r)
+			space = false
+		} else if unicode.Is(unicode.Mn, r) || r == '\'' || r == '’' || r == 'ʻ' || r == 'ʼ' {
+			continue
+		} else if !space {
+			buf.WriteByte(' ')
+			space = true
+		}
+	}
+	return strings.TrimRight(buf.String(), " ")
+}
+
+//	Returns `Fold(name)` and, if different, the `Fold`ed conventional transliteration of `name`
+//	(eg. `muenchen` in addition to `munchen` for `München`), omitting empty keys.
+func Keys(name string) (keys []string) {
+	if key := Fold(name); len(key) > 0 {
+		keys = append(keys, key)
+		if tkey := Fold(transliterations.Replace(name)); tkey != key {
+			keys = append(keys, tkey)
+		}
+	}
+	return
+}
+
+func hasKey(name, key string) bool {
+	for _, k := range Keys(name) {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+package geonames_search
+
+import (
+	"math"
+	"strings"
+)
+
+var (
+	//	Letter groups replaced before `Phonetic` coding
+	phoneticDigraphs = strings.NewReplacer("sch", "s", "ph", "f", "ck", "k", "th", "t", "gh", "g", "dt", "t", "ae", "a", "oe", "o", "ue", "u")
+
+	//	`Phonetic` codes per letter: `0` for vowels (kept only in first position), `-` for silent letters
+	phoneticCodes = [26]byte{'0', '1', '2', '3', '0', '1', '2', '-', '0', '2', '2', '4', '5', '5', '0', '1', '2', '6', '2', '3', '0', '1', '-', '2', '0', '2'}
+)
+
+//	Returns a Soundex-like (but untruncated and per-word) phonetic code of `name`, so that similar-sounding names
+//	(such as `Duesseldorf` and `Dusseldorf`, or `Zurich` and `Surich`) share the same code.
+//	Names that differ beyond spelling (such as `Munich` and `München`) are matched via `NamesAlt` and alternate names instead.
+func Phonetic(name string) string {
+	var buf strings.Builder
+	for w, word := range strings.Fields(phoneticDigraphs.Replace(Fold(name))) {
+		if w > 0 {
+			buf.WriteByte(' ')
+		}
+		var last byte
+		for i := 0; i < len(word); i++ {
+			code := byte('?')
+			if c := word[i]; c >= 'a' && c <= 'z' {
+				code = phoneticCodes[c-'a']
+			} else if c >= '0' && c <= '9' {
+				code = c
+			}
+			if code != last && code != '-' && (code != '0' || i == 0) {
+				buf.WriteByte(code)
+			}
+			if code != '-' {
+				last = code
+			}
+		}
+	}
+	return buf.String()
+}
+
+//	Returns the optimal-string-alignment distance (insertions, deletions, substitutions and adjacent transpositions) between `a` and `b`,
+//	or any value greater than `max` once it is known to exceed `max`.
+func EditDistance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > max || -d > max {
+		return max + 1
+	}
+	prev2, prev, cur := make([]int, len(rb)+1), make([]int, len(rb)+1), make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] && prev2[j-2]+1 < cur[j] {
+				cur[j] = prev2[j-2] + 1
+			}
+			if cur[j] < rowMin {
+				rowMin = cur[j]
+			}
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+//	Returns `1 - distance / length` for the longer of `a` and `b`.
+func similarity(a, b string, distance int) float64 {
+	n := len([]rune(a))
+	if m := len([]rune(b)); m > n {
+		n = m
+	}
+	if n == 0 || distance >= n {
+		return 0
+	}
+	return 1 - float64(distance)/float64(n)
+}
+
+//	A BK-tree of index keys by `EditDistance`, stored in flat slices: each node's first child, next sibling and distance to its parent.
+type bkTree struct {
+	keys  []string
+	first []int32
+	next  []int32
+	dists []uint16
+}
+
+//	The `EditDistance` of `a` and `b`, clamped to `math.MaxUint16` (for keys of more than 65534 letters) so that it fits `bkTree.dists`.
+//	Both `add` and `find` use it, so the tree stays consistent even then.
+func bkDistance(a, b string) int {
+	return EditDistance(a, b, math.MaxUint16-1)
+}
+
+func (me *bkTree) add(key string) {
+	if len(me.keys) == 0 {
+		me.keys, me.first, me.next, me.dists = append(me.keys, key), append(me.first, -1), append(me.next, -1), append(me.dists, 0)
+		return
+	}
+	for node := int32(0); ; {
+		d := bkDistance(me.keys[node], key)
+		if d == 0 {
+			return
+		}
+		child := me.first[node]
+		for child >= 0 && int(me.dists[child]) != d {
+			child = me.next[child]
+		}
+		if child < 0 {
+			n := int32(len(me.keys))
+			me.keys, me.first, me.next, me.dists = append(me.keys, key), append(me.first, -1), append(me.next, me.first[node]), append(me.dists, uint16(d))
+			me.first[node] = n
+			return
+		}
+		node = child
+	}
+}
+
+func (me *bkTree) find(key string, radius int, onMatch func(key string, distance int)) {
+	if len(me.keys) == 0 {
+		return
+	}
+	stack := []int32{0}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		d := bkDistance(me.keys[node], key)
+		if d <= radius {
+			onMatch(me.keys[node], d)
+		}
+		for child := me.first[node]; child >= 0; child = me.next[child] {
+			if cd := int(me.dists[child]); cd >= d-radius && cd <= d+radius {
+				stack = append(stack, child)
+			}
+		}
+	}
+}
+
+//	Controls `Index.FindFuzzy`.
+type FuzzyOptions struct {
+	//	Maximum `EditDistance` for query names of at least 5 letters (shorter ones allow one edit less, 3 letters or fewer none)
+	MaxEdits int
+
+	//	Whether to also match names sharing the query's `Phonetic` code (regardless of `MaxEdits`, but scored by `0.9` times their
+	//	edit `Similarity`, so that `MinSimilarity` applies to them, too)
+	Phonetic bool
+
+	//	Candidates with a lower `Similarity` are dropped
+	MinSimilarity float64
+}
+
+var (
+	//	Used by `Index.FindFuzzy` if no `FuzzyOptions` are given
+	FuzzyDefaults = FuzzyOptions{MaxEdits: 2, Phonetic: true, MinSimilarity: 0.5}
+)
+
+//	Prepares `me` for `FindFuzzy` by indexing all its name keys by edit distance and `Phonetic` code.
+//	Must be called after `AddAlternateNames` (if at all), and before concurrent use.
+func (me *Index) BuildFuzzy() {
+	me.bk, me.phonetics = &bkTree{}, map[string][]string{}
+	for key := range me.names {
+		me.bk.add(key)
+		if code := Phonetic(key); len(code) > 0 {
+			me.phonetics[code] = append(me.phonetics[code], key)
+		}
+	}
+}
+
+//	Same as `FindFuzzy(ParseQuery(text), max, opts)`.
+func (me *Index) SearchFuzzy(text string, max int, opts *FuzzyOptions) []Candidate {
+	return me.FindFuzzy(ParseQuery(text), max, opts)
+}
+
+//	Like `Find`, but also returns places whose names are within `opts.MaxEdits` of `q.Name` or (optionally) sound alike,
+//	scored by both their `Similarity` and `Rank`. Without a prior `BuildFuzzy`, only exact and transliterated matches are found.
+//	`opts` may be `nil` to use `FuzzyDefaults`.
+func (me *Index) FindFuzzy(q Query, max int, opts *FuzzyOptions) (candidates []Candidate) {
+	if opts == nil {
+		opts = &FuzzyDefaults
+	}
+	found, keys := map[int32]int{}, Keys(q.Name)
+	for _, key := range keys {
+		me.collect(&q, key, 1, &candidates, found)
+	}
+	if me.bk != nil && len(keys) > 0 {
+		maxEdits := opts.MaxEdits
+		if n := len([]rune(keys[0])); n <= 3 {
+			maxEdits = 0
+		} else if n < 5 && maxEdits > 0 {
+			maxEdits--
+		}
+		for _, qkey := range keys {
+			me.bk.find(qkey, maxEdits, func(key string, distance int) {
+				if sim := similarity(qkey, key, distance); distance > 0 && sim >= opts.MinSimilarity {
+					me.collect(&q, key, sim, &candidates, found)
+				}
+			})
+		}
+		if opts.Phonetic {
+			for _, key := range me.phonetics[Phonetic(keys[0])] {
+				if sim := 0.9 * similarity(keys[0], key, EditDistance(keys[0], key, len(key)+len(keys[0]))); sim >= opts.MinSimilarity {
+					me.collect(&q, key, sim, &candidates, found)
+				}
+			}
+		}
+	}
+	sortAndLimit(&candidates, max)
+	return
+}
@@ -0,0 +1,85 @@
+package geonames_search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-geo/geonames/import-dumps"
+	"github.com/go-geo/geonames/mem-store"
+)
+
+func TestPhonetic(t *testing.T) {
+	for _, names := range [][]string{
+		{"Muenchen", "München", "Munchen"},
+		{"Duesseldorf", "Dusseldorf", "Düsseldorf"},
+		{"Zurich", "Surich", "Zürich"},
+	} {
+		for _, name := range names[1:] {
+			if code, expected := Phonetic(name), Phonetic(names[0]); code != expected {
+				t.Errorf("Phonetic(%#v) = %#v, expected %#v as for %#v", name, code, expected, names[0])
+			}
+		}
+	}
+	for _, pair := range [][2]string{{"Munich", "Berlin"}, {"Bremen", "Brem"}, {"Bergen", "Berg"}, {"Essen", "Ess"}} {
+		if Phonetic(pair[0]) == Phonetic(pair[1]) {
+			t.Errorf("%s and %s share a phonetic code", pair[0], pair[1])
+		}
+	}
+}
+
+func TestFindFuzzyMunich(t *testing.T) {
+	store := geonames_mem.NewStore()
+	for i, name := range []string{"München", "Berlin", "Hamburg"} {
+		p := &geonames_import.Place{}
+		p.Id, p.Name, p.Population = int64(i+1), name, 1000000
+		p.Feature.Class, p.Feature.Code, p.Country.Code = "P", "PPLA", "DE"
+		store.Places = append(store.Places, p)
+	}
+	store.Places[0].NamesAlt = []string{"Munich", "Monaco di Baviera"}
+	ix := NewIndex(store, nil)
+	ix.BuildFuzzy()
+	for _, q := range []string{"Munich", "Munik", "Muenchen", "Munchen", "Muenchn"} {
+		if candidates := ix.SearchFuzzy(q, 3, nil); len(candidates) == 0 || candidates[0].Place.Id != 1 {
+			t.Errorf("SearchFuzzy(%#v) did not find München first: %v", q, candidates)
+		}
+	}
+}
+
+func TestPhoneticMinSimilarity(t *testing.T) {
+	store := geonames_mem.NewStore()
+	p := &geonames_import.Place{}
+	p.Id, p.Name, p.Feature.Class, p.Feature.Code, p.Country.Code = 1, "Schwarzenbach", "P", "PPL", "DE"
+	store.Places = append(store.Places, p)
+	ix := NewIndex(store, nil)
+	ix.BuildFuzzy()
+	// both sound like `Schwarzenbach`, but are more than `MaxEdits` apart from it, the latter too far for `MinSimilarity`
+	for q, expected := range map[string]int{"Swarzenbak": 1, "Swarzenpaakk": 0} {
+		if Phonetic(q) != Phonetic(p.Name) {
+			t.Fatalf("%#v and %#v differ phonetically", q, p.Name)
+		}
+		if candidates := ix.SearchFuzzy(q, 3, nil); len(candidates) != expected {
+			t.Errorf("SearchFuzzy(%#v): expected %d candidates, got %v", q, expected, candidates)
+		}
+	}
+}
+
+func TestBkTreeLongKeys(t *testing.T) {
+	long := strings.Repeat("a", 300)
+	keys := []string{"", long, long + "b", strings.Repeat("b", 300), "abc"}
+	var bk bkTree
+	for _, key := range keys {
+		bk.add(key)
+	}
+	for _, key := range keys {
+		found := false
+		bk.find(key, 0, func(k string, d int) { found = found || (k == key && d == 0) })
+		if !found {
+			t.Errorf("key of length %d not found", len(key))
+		}
+	}
+	var near []string
+	bk.find(long, 1, func(k string, _ int) { near = append(near, k) })
+	if len(near) != 2 {
+		t.Errorf("expected 2 keys within 1 edit of the long key, got %d", len(near))
+	}
+}
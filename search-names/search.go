@@ -0,0 +1,257 @@
+//	Forward geocoding: finds places by name (optionally qualified by admin division and/or country) among the places of a `mem-store`,
+//	ranked by name match, feature code and population.
+package geonames_search
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/go-geo/geonames/import-dumps"
+	"github.com/go-geo/geonames/mem-store"
+	"github.com/go-geo/geonames/parse-dumps"
+)
+
+var (
+	//	Relative importance (`0..1`) of feature codes for `Rank`. Codes not listed here get `FeatureWeightDefault`.
+	FeatureWeights = map[string]float64{
+		"PCLI": 1, "PCLD": 0.95, "PCLS": 0.95, "PPLC": 1, "PPLG": 0.9, "PPLA": 0.9, "PPLA2": 0.8, "PPLA3": 0.7, "PPLA4": 0.65,
+		"ADM1": 0.75, "ADM2": 0.65, "ADM3": 0.55, "ADM4": 0.5, "PPL": 0.6, "PPLS": 0.55, "PPLL": 0.5, "PPLF": 0.45,
+		"PPLR": 0.45, "PPLW": 0.25, "PPLQ": 0.2, "PPLH": 0.2, "PPLCH": 0.3, "PPLX": 0.3,
+	}
+
+	//	Weight of feature codes not listed in `FeatureWeights`
+	FeatureWeightDefault = 0.4
+
+	//	Languages (or pseudo-languages) of alternate names not indexed by `Index.AddAlternateNames`
+	AlternateNamesIgnored = map[string]bool{
+		"link": true, "post": true, "iata": true, "icao": true, "faac": true, "fr_1793": true, "tcid": true, "unlc": true, "wkdt": true,
+	}
+)
+
+//	How a name matched, from best to worst.
+const (
+	MatchName = iota
+	MatchNameAscii
+	MatchNamesAlt
+	MatchAlternateName
+)
+
+var (
+	matchScores = [...]float64{MatchName: 1, MatchNameAscii: 0.95, MatchNamesAlt: 0.85, MatchAlternateName: 0.8}
+)
+
+//	Returns a `0..1` importance estimate of `p`, averaging its `FeatureWeights` and its (logarithmic) population.
+func Rank(p *geonames_import.Place) float64 {
+	fw, ok := FeatureWeights[p.Feature.Code]
+	if !ok {
+		fw = FeatureWeightDefault
+	}
+	return (fw + math.Min(1, math.Log10(float64(p.Population)+1)/7)) / 2
+}
+
+type entry struct {
+	place int32
+	match uint8
+}
+
+//	A read-only name index over (a subset of) the `Places` of a `geonames_mem.Store`, safe for concurrent use once built.
+type Index struct {
+	//	The records looked up by `Find`
+	Store *geonames_mem.Store
+
+	//	All indexed places
+	Places []*geonames_import.Place
+
+	names     map[string][]entry
+	placeIds  map[int64]int32
+	bk        *bkTree
+	phonetics map[string][]string
+}
+
+//	Builds an `Index` over the `Name`, `NameAscii` and `NamesAlt` of all `store.Places` for which `include` returns `true` (or all of them if `include` is `nil`).
+func NewIndex(store *geonames_mem.Store, include func(*geonames_import.Place) bool) (me *Index) {
+	me = &Index{Store: store, names: map[string][]entry{}, placeIds: map[int64]int32{}}
+	for _, p := range store.Places {
+		if include == nil || include(p) {
+			i := int32(len(me.Places))
+			me.Places, me.placeIds[p.Id] = append(me.Places, p), i
+			me.add(p.Name, i, MatchName)
+			me.add(p.NameAscii, i, MatchNameAscii)
+			for _, n := range p.NamesAlt {
+				me.add(n, i, MatchNamesAlt)
+			}
+		}
+	}
+	return
+}
+
+//	Adds all alternate names in `geo.FileNames.AlternateNames` (of places already indexed) to `me`, skipping `AlternateNamesIgnored`.
+func (me *Index) AddAlternateNames(geo *geonames_parse.Iterator) error {
+	return geo.AlternateNames(func(_ int, r *geonames_parse.AlternateNameRec) {
+		if i, ok := me.placeIds[r.PlaceId]; ok && !AlternateNamesIgnored[r.Language] {
+			me.add(r.Name, i, MatchAlternateName)
+		}
+	})
+}
+
+func (me *Index) add(name string, place int32, match uint8) {
+	for _, key := range Keys(name) {
+		me.addKey(key, place, match)
+	}
+}
+
+func (me *Index) addKey(key string, place int32, match uint8) {
+	entries := me.names[key]
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].place == place {
+			if match < entries[i].match {
+				entries[i].match = match
+			}
+			return
+		}
+	}
+	me.names[key] = append(entries, entry{place: place, match: match})
+}
+
+//	A parsed search query. All non-empty qualifiers must be met by a `Candidate`.
+type Query struct {
+	//	The place name searched for
+	Name string
+
+	//	First-order administrative division: code (eg. `IL`) or name (eg. `Illinois`)
+	Admin1 string
+
+	//	Country: ISO-3166 alpha-2 or alpha-3 code, or name
+	Country string
+
+	//	Each must match either the `Admin1` or the `Country` of a `Candidate`
+	Qualifiers []string
+}
+
+//	Parses comma-separated free text such as `Springfield, IL` or `Paris, Île-de-France, FR` into a `Query`:
+//	the first part is its `Name`, all others are its `Qualifiers`.
+func ParseQuery(text string) (q Query) {
+	for i, part := range strings.Split(text, ",") {
+		if part = strings.TrimSpace(part); i == 0 {
+			q.Name = part
+		} else if len(part) > 0 {
+			q.Qualifiers = append(q.Qualifiers, part)
+		}
+	}
+	return
+}
+
+//	A search result.
+type Candidate struct {
+	//	The place found
+	Place *geonames_import.Place
+
+	//	The place's country and first-order administrative division, each `nil` if unknown
+	Country *geonames_import.Country
+	Admin1  *geonames_import.Admin
+
+	//	The place name that matched the query
+	MatchedName string
+
+	//	One of the `Match*` constants
+	Match int
+
+	//	How similar (`0..1`) the matched name is to the queried one: `1` unless found by `FindFuzzy`
+	Similarity float64
+
+	//	Relevance, higher is better
+	Score float64
+}
+
+//	Same as `Find(ParseQuery(text), max)`.
+func (me *Index) Search(text string, max int) []Candidate {
+	return me.Find(ParseQuery(text), max)
+}
+
+//	Returns up to `max` (or all, if `max` is `0`) places matching `q`, best first.
+func (me *Index) Find(q Query, max int) (candidates []Candidate) {
+	found := map[int32]int{}
+	for _, key := range Keys(q.Name) {
+		me.collect(&q, key, 1, &candidates, found)
+	}
+	sortAndLimit(&candidates, max)
+	return
+}
+
+//	Appends all places indexed under `key` that meet `q` to `candidates`, unless already `found` with a better `Score`.
+func (me *Index) collect(q *Query, key string, similarity float64, candidates *[]Candidate, found map[int32]int) {
+	for _, e := range me.names[key] {
+		if c, ok := me.candidate(q, e.place, int(e.match), key); ok {
+			c.Similarity, c.Score = similarity, similarity*matchScores[c.Match]*(0.2+0.8*Rank(c.Place))
+			if i, dup := found[e.place]; !dup {
+				found[e.place], *candidates = len(*candidates), append(*candidates, c)
+			} else if c.Score > (*candidates)[i].Score {
+				(*candidates)[i] = c
+			}
+		}
+	}
+}
+
+func sortAndLimit(candidates *[]Candidate, max int) {
+	c := *candidates
+	sort.SliceStable(c, func(i, j int) bool {
+		if c[i].Score == c[j].Score {
+			return c[i].Place.Id < c[j].Place.Id
+		}
+		return c[i].Score > c[j].Score
+	})
+	if max > 0 && len(c) > max {
+		*candidates = c[:max]
+	}
+}
+
+func (me *Index) candidate(q *Query, place int32, match int, key string) (c Candidate, ok bool) {
+	c.Place, c.Match = me.Places[place], match
+	if c.Country = me.Store.Country(c.Place.CountryRef); c.Country == nil {
+		c.Country = me.Store.CountryByCode(c.Place.Country.Code)
+	}
+	if len(c.Place.Admin.Code1) > 0 {
+		c.Admin1 = me.Store.AdminByCode(c.Place.Country.Code + "." + c.Place.Admin.Code1)
+	}
+	if ok = (len(q.Admin1) == 0 || c.matchesAdmin1(q.Admin1)) && (len(q.Country) == 0 || c.matchesCountry(q.Country)); ok {
+		for _, qual := range q.Qualifiers {
+			if ok = c.matchesAdmin1(qual) || c.matchesCountry(qual); !ok {
+				break
+			}
+		}
+	}
+	if ok {
+		switch match {
+		case MatchName:
+			c.MatchedName = c.Place.Name
+		case MatchNameAscii:
+			c.MatchedName = c.Place.NameAscii
+		case MatchNamesAlt:
+			for _, n := range c.Place.NamesAlt {
+				if hasKey(n, key) {
+					c.MatchedName = n
+					break
+				}
+			}
+		}
+		if len(c.MatchedName) == 0 {
+			c.MatchedName = q.Name
+		}
+	}
+	return
+}
+
+func (me *Candidate) matchesAdmin1(qual string) bool {
+	qual = Fold(qual)
+	return len(me.Place.Admin.Code1) > 0 && (qual == Fold(me.Place.Admin.Code1) ||
+		(me.Admin1 != nil && (qual == Fold(me.Admin1.Name) || qual == Fold(me.Admin1.NameAscii))))
+}
+
+func (me *Candidate) matchesCountry(qual string) bool {
+	qual = Fold(qual)
+	if me.Country == nil {
+		return qual == Fold(me.Place.Country.Code)
+	}
+	return qual == Fold(me.Country.Code.Iso2) || qual == Fold(me.Country.Code.Iso3) || qual == Fold(me.Country.Name)
+}
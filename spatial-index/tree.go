@@ -0,0 +1,208 @@
+//	A static k-d tree for nearest-neighbor queries over lon/lat coordinates, stored in flat slices so it can be persisted and reloaded as-is.
+package geonames_spatial
+
+import (
+	"container/heap"
+	"math"
+)
+
+const (
+	//	Mean earth radius in kilometers
+	EarthRadiusKm = 6371.0088
+)
+
+//	A point on the unit sphere.
+type Point [3]float64
+
+//	Converts the specified coordinates (in degrees) to a `Point`.
+func NewPoint(lon, lat float64) (p Point) {
+	lon, lat = lon*math.Pi/180, lat*math.Pi/180
+	coslat := math.Cos(lat)
+	p[0], p[1], p[2] = coslat*math.Cos(lon), coslat*math.Sin(lon), math.Sin(lat)
+	return
+}
+
+//	Returns the squared straight-line distance between `me` and `p`.
+func (me Point) ChordSq(p Point) float64 {
+	dx, dy, dz := me[0]-p[0], me[1]-p[1], me[2]-p[2]
+	return dx*dx + dy*dy + dz*dz
+}
+
+//	Converts a squared chord length between two unit-sphere `Point`s into a great-circle distance in kilometers.
+func ChordSqToKm(chordSq float64) float64 {
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(chordSq)/2))
+}
+
+//	Converts a great-circle distance in kilometers into the squared chord length between two unit-sphere `Point`s.
+func KmToChordSq(km float64) float64 {
+	chord := 2 * math.Sin(math.Min(math.Pi/2, km/(2*EarthRadiusKm)))
+	return chord * chord
+}
+
+//	Returns the great-circle distance in kilometers between the specified coordinates (in degrees).
+func DistanceKm(lon1, lat1, lon2, lat2 float64) float64 {
+	return ChordSqToKm(NewPoint(lon1, lat1).ChordSq(NewPoint(lon2, lat2)))
+}
+
+//	A balanced k-d tree whose node for any slice range `[lo, hi)` sits at its middle `(lo + hi) / 2`,
+//	with the nodes of its "lesser" and "greater" sub-trees in `[lo, mid)` and `(mid, hi)` respectively.
+type Tree struct {
+	//	Node coordinates, in tree order
+	Points []Point
+
+	//	For each node, the item index passed to `NewTree`
+	Items []int32
+
+	//	For each node, its split axis (`0`, `1` or `2`)
+	Axes []uint8
+}
+
+//	Builds a `Tree` over the specified coordinates, where `lonLat(i)` returns the lon/lat of item `i` (in degrees) for `0 <= i < count`.
+func NewTree(count int, lonLat func(int) (float64, float64)) (me *Tree) {
+	me = &Tree{Points: make([]Point, count), Items: make([]int32, count), Axes: make([]uint8, count)}
+	for i := 0; i < count; i++ {
+		me.Points[i], me.Items[i] = NewPoint(lonLat(i)), int32(i)
+	}
+	me.build(0, count)
+	return
+}
+
+func (me *Tree) build(lo, hi int) {
+	if hi-lo < 1 {
+		return
+	}
+	var min, max Point
+	for d := 0; d < 3; d++ {
+		min[d], max[d] = math.Inf(1), math.Inf(-1)
+	}
+	for _, p := range me.Points[lo:hi] {
+		for d := 0; d < 3; d++ {
+			min[d], max[d] = math.Min(min[d], p[d]), math.Max(max[d], p[d])
+		}
+	}
+	axis := 0
+	for d := 1; d < 3; d++ {
+		if max[d]-min[d] > max[axis]-min[axis] {
+			axis = d
+		}
+	}
+	mid := (lo + hi) / 2
+	me.selectNth(lo, hi-1, mid, axis)
+	me.Axes[mid] = uint8(axis)
+	me.build(lo, mid)
+	me.build(mid+1, hi)
+}
+
+//	Partially sorts `[lo, hi]` such that position `n` holds the node it would hold if fully sorted along `axis`.
+func (me *Tree) selectNth(lo, hi, n, axis int) {
+	for hi > lo {
+		pivot := me.Points[(lo+hi)/2][axis]
+		i, j := lo, hi
+		for i <= j {
+			for me.Points[i][axis] < pivot {
+				i++
+			}
+			for me.Points[j][axis] > pivot {
+				j--
+			}
+			if i <= j {
+				me.swap(i, j)
+				i, j = i+1, j-1
+			}
+		}
+		if n <= j {
+			hi = j
+		} else if n >= i {
+			lo = i
+		} else {
+			break
+		}
+	}
+}
+
+func (me *Tree) swap(i, j int) {
+	me.Points[i], me.Points[j] = me.Points[j], me.Points[i]
+	me.Items[i], me.Items[j] = me.Items[j], me.Items[i]
+}
+
+//	A nearest-neighbor result.
+type Neighbor struct {
+	//	The item index passed to `NewTree`
+	Item int
+
+	//	Great-circle distance in kilometers
+	DistanceKm float64
+}
+
+//	Returns up to `k` items nearest to the specified coordinates (in degrees), nearest first.
+//
+//	Only items within `maxKm` (if `> 0`) and satisfying `accept` (if not `nil`) are considered.
+func (me *Tree) Nearest(lon, lat float64, k int, maxKm float64, accept func(item int) bool) (nearest []Neighbor) {
+	if k < 1 || len(me.Points) == 0 {
+		return
+	}
+	s := search{tree: me, pt: NewPoint(lon, lat), k: k, accept: accept, worst: math.Inf(1)}
+	if maxKm > 0 {
+		s.worst = KmToChordSq(maxKm)
+	}
+	s.visit(0, len(me.Points))
+	nearest = make([]Neighbor, len(s.found))
+	for i := len(nearest) - 1; i >= 0; i-- {
+		c := heap.Pop(&s.found).(candidate)
+		nearest[i] = Neighbor{Item: int(me.Items[c.node]), DistanceKm: ChordSqToKm(c.chordSq)}
+	}
+	return
+}
+
+type candidate struct {
+	node    int
+	chordSq float64
+}
+
+//	A max-heap of `candidate`s by `chordSq`.
+type candidates []candidate
+
+func (me candidates) Len() int            { return len(me) }
+func (me candidates) Less(i, j int) bool  { return me[i].chordSq > me[j].chordSq }
+func (me candidates) Swap(i, j int)       { me[i], me[j] = me[j], me[i] }
+func (me *candidates) Push(x interface{}) { *me = append(*me, x.(candidate)) }
+func (me *candidates) Pop() (x interface{}) {
+	old := *me
+	x, *me = old[len(old)-1], old[:len(old)-1]
+	return
+}
+
+type search struct {
+	tree   *Tree
+	pt     Point
+	k      int
+	accept func(int) bool
+	found  candidates
+	worst  float64
+}
+
+func (me *search) visit(lo, hi int) {
+	if hi <= lo {
+		return
+	}
+	mid := (lo + hi) / 2
+	node := &me.tree.Points[mid]
+	if d := me.pt.ChordSq(*node); d <= me.worst && (me.accept == nil || me.accept(int(me.tree.Items[mid]))) {
+		if heap.Push(&me.found, candidate{node: mid, chordSq: d}); len(me.found) > me.k {
+			heap.Pop(&me.found)
+		}
+		if len(me.found) == me.k {
+			me.worst = me.found[0].chordSq
+		}
+	}
+	axis := me.tree.Axes[mid]
+	diff := me.pt[axis] - node[axis]
+	nearLo, nearHi, farLo, farHi := lo, mid, mid+1, hi
+	if diff > 0 {
+		nearLo, nearHi, farLo, farHi = mid+1, hi, lo, mid
+	}
+	me.visit(nearLo, nearHi)
+	if diff*diff <= me.worst {
+		me.visit(farLo, farHi)
+	}
+}
@@ -0,0 +1,134 @@
+//	Resolves arbitrary coordinates to IANA time zones via the nearest populated places of a `reverse-geo` index,
+//	and their current UTC offsets and DST status via `time.LoadLocation`.
+package geonames_tz
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-geo/geonames/import-dumps"
+	"github.com/go-geo/geonames/reverse-geo"
+)
+
+//	A time-zone resolution result.
+type Result struct {
+	//	IANA time zone name, eg. `Europe/Berlin`
+	Name string
+
+	//	The place whose time zone was used, or `nil` if `Name` was derived from its country (having only one time zone)
+	Place *geonames_import.Place
+
+	//	Great-circle distance in kilometers from the queried coordinates to the nearest populated place
+	DistanceKm float64
+
+	//	The country of the nearest populated place, or `nil` if unknown
+	Country *geonames_import.Country
+
+	//	The static record of `Name` from `timeZones.txt`, or `nil` if unknown
+	Timezone *geonames_import.Timezone
+
+	//	The loaded `Name`, or `nil` if `time.LoadLocation` failed (such as for lack of a time zone database)
+	Location *time.Location
+
+	//	The UTC offset and zone abbreviation (eg. `CEST`) at the queried time, as per `Location`.
+	//	If `Location` is `nil`, `Offset` falls back to `Timezone.OffsetRaw` and `Abbrev` is empty.
+	Offset time.Duration
+	Abbrev string
+
+	//	Whether daylight saving time is in effect at the queried time (always `false` if `Location` is `nil`)
+	IsDST bool
+}
+
+//	Resolves coordinates to time zones. Safe for concurrent use.
+type Resolver struct {
+	//	Provides the nearest places
+	Reverse *geonames_reverse.Index
+
+	//	How many nearest populated places to consider, defaults to `8`
+	Candidates int
+
+	//	Coordinates farther than this from any populated place (such as in the open sea) resolve to nothing. `0` means no limit.
+	MaxDistanceKm float64
+
+	zones     map[string][]string
+	locations sync.Map
+}
+
+//	Initializes a new `Resolver` over the places and time zones of `reverse.Store`.
+func NewResolver(reverse *geonames_reverse.Index) (me *Resolver) {
+	me = &Resolver{Reverse: reverse, Candidates: 8, zones: map[string][]string{}}
+	for _, tz := range reverse.Store.Timezones {
+		me.zones[tz.CountryCode] = append(me.zones[tz.CountryCode], tz.TimezoneName)
+	}
+	return
+}
+
+//	Same as `At(lon, lat, time.Now())`.
+func (me *Resolver) Lookup(lon, lat float64) (Result, bool) {
+	return me.At(lon, lat, time.Now())
+}
+
+//	Resolves `lon`/`lat` (in degrees) to the time zone of the nearest populated place, and its UTC offset and DST status at `t`.
+//	Returns `false` if no populated place lies within `MaxDistanceKm`.
+//
+//	As a sanity check against borders, places in a country other than that of the nearest place, or whose time zone
+//	is not listed for their country, are passed over in favour of the next-nearest place that is.
+func (me *Resolver) At(lon, lat float64, t time.Time) (r Result, ok bool) {
+	var nearest []geonames_reverse.Result
+	if nearest = me.Reverse.Nearest(lon, lat, me.Candidates, &geonames_reverse.Filter{FeatureClasses: "P", MaxDistanceKm: me.MaxDistanceKm}); len(nearest) == 0 {
+		return
+	}
+	r.DistanceKm, r.Country = nearest[0].DistanceKm, nearest[0].Country
+	country := nearest[0].Place.Country.Code
+	for _, n := range nearest {
+		if len(n.Timezone) > 0 && n.Place.Country.Code == country && me.valid(country, n.Timezone) {
+			r.Name, r.Place = n.Timezone, n.Place
+			break
+		}
+	}
+	if len(r.Name) == 0 {
+		if zones := me.zones[country]; len(zones) == 1 {
+			r.Name = zones[0]
+		} else {
+			for _, n := range nearest {
+				if len(n.Timezone) > 0 {
+					r.Name, r.Place = n.Timezone, n.Place
+					break
+				}
+			}
+		}
+	}
+	if ok = len(r.Name) > 0; ok {
+		r.Timezone = me.Reverse.Store.TimezoneByName(r.Name)
+		if r.Location = me.location(r.Name); r.Location != nil {
+			var secs int
+			r.Abbrev, secs = t.In(r.Location).Zone()
+			r.Offset, r.IsDST = time.Duration(secs)*time.Second, t.In(r.Location).IsDST()
+		} else if r.Timezone != nil {
+			r.Offset = time.Duration(r.Timezone.OffsetRaw * float64(time.Hour))
+		}
+	}
+	return
+}
+
+func (me *Resolver) location(name string) *time.Location {
+	if loc, ok := me.locations.Load(name); ok {
+		return loc.(*time.Location)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		loc = nil
+	}
+	me.locations.Store(name, loc)
+	return loc
+}
+
+func (me *Resolver) valid(country, zone string) bool {
+	zones := me.zones[country]
+	for _, z := range zones {
+		if z == zone {
+			return true
+		}
+	}
+	return len(zones) == 0
+}